@@ -0,0 +1,59 @@
+package robotstxt
+
+import (
+	netUrl "net/url"
+	"sort"
+)
+
+/*
+AgentsAllowed returns the robots (user-agents) named in the robots.txt that can crawl a URL, sorted by name and written the way the
+robots.txt writes them. Every group is evaluated, the group of "*" as well, so "*" is one of them when the robots.txt has a group
+for it and robots that are not named can crawl the URL:
+
+	User-agent: *
+	Disallow: /cms/
+
+	User-agent: AdsBot-Google
+	Allow: /
+
+	agents, err := robotsTxt.AgentsAllowed("/cms/pages")
+	// agents = []string{"AdsBot-Google"}
+
+The URL is either a path or an absolute URL the same way as for CanCrawl, an error is returned whenever CanCrawl would return one for
+any of the robots.
+*/
+func (robotsTxt *RobotsTxt) AgentsAllowed(url string) ([]string, error) {
+	return robotsTxt.agentsCrawling(url, true)
+}
+
+// AgentsBlocked returns the robots (user-agents) named in the robots.txt that can not crawl a URL, the ones AgentsAllowed leaves
+// out.
+func (robotsTxt *RobotsTxt) AgentsBlocked(url string) ([]string, error) {
+	return robotsTxt.agentsCrawling(url, false)
+}
+
+// agentsCrawling returns the robots named in the robots.txt for which CanCrawl returns canCrawl.
+func (robotsTxt *RobotsTxt) agentsCrawling(url string, canCrawl bool) ([]string, error) {
+	parsedUrl, err := netUrl.Parse(url)
+	if err != nil {
+		return nil, err
+	}
+
+	agents := []string{}
+	names := keys(robotsTxt.robots)
+	sort.Strings(names)
+	for _, name := range names {
+		if name == "" {
+			continue // An empty User-agent line names no robot.
+		}
+		// Every robot that is named follows its own group, even when the name starts with the name of another group.
+		allowed, _, err := robotsTxt.canCrawl(robotsTxt.robots[name], true, parsedUrl)
+		if err != nil {
+			return nil, err
+		}
+		if allowed == canCrawl {
+			agents = append(agents, name)
+		}
+	}
+	return agents, nil
+}
@@ -0,0 +1,75 @@
+package robotstxt_test
+
+import (
+	"fmt"
+	"github.com/itmayziii/robotstxt/v2"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestRobotsTxt_AgentsAllowed_and_AgentsBlocked(t *testing.T) {
+	robotsTxt := newRobotsTxt(t, `User-agent: *
+Disallow: /cms/
+
+User-agent: Googlebot
+User-agent: bingbot
+Disallow: /private/
+
+User-agent: Googlebot-News
+Disallow: /
+
+User-agent:
+Disallow: /products/
+`)
+
+	allowed, err := robotsTxt.AgentsAllowed("/cms/pages")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Googlebot", "bingbot"}, allowed)
+	blocked, err := robotsTxt.AgentsBlocked("/cms/pages")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"*", "Googlebot-News"}, blocked)
+
+	// Googlebot-News follows its own group and not the group of Googlebot.
+	allowed, err = robotsTxt.AgentsAllowed("https://www.dumpsters.com/products/")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"*", "Googlebot", "bingbot"}, allowed)
+	blocked, err = robotsTxt.AgentsBlocked("https://www.dumpsters.com/products/")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Googlebot-News"}, blocked)
+
+	blocked, err = robotsTxt.AgentsBlocked("/about")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Googlebot-News"}, blocked)
+
+	_, err = robotsTxt.AgentsAllowed("https://www.example.com/cms/")
+	assert.Equal(t, robotstxt.ErrOriginMismatch, err)
+	_, err = robotsTxt.AgentsBlocked("%")
+	assert.NotNil(t, err)
+
+	robotsTxt = newRobotsTxt(t, "User-agent: googlebot\nDisallow: /search/(*\n")
+	blocked, err = robotsTxt.AgentsBlocked("/search/(a)")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"googlebot"}, blocked)
+	blocked, err = robotsTxt.AgentsBlocked("/search/a")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{}, blocked)
+
+	// No group applies to robots that are not named.
+	allowed, err = newRobotsTxt(t, "User-agent: googlebot\nDisallow: /\n").AgentsAllowed("/")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{}, allowed)
+}
+
+func ExampleRobotsTxt_AgentsAllowed() {
+	robotsTxt, _ := robotstxt.New("https://www.dumpsters.com", strings.NewReader(`User-agent: *
+Disallow: /cms/
+
+User-agent: AdsBot-Google
+Allow: /
+`))
+	allowed, _ := robotsTxt.AgentsAllowed("/cms/pages")
+	blocked, _ := robotsTxt.AgentsBlocked("/cms/pages")
+	fmt.Println(allowed, blocked)
+	// Output: [AdsBot-Google] [*]
+}
@@ -0,0 +1,411 @@
+package robotstxt
+
+import (
+	"encoding/binary"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// pathAlphabet are the bytes a path can be made of once it is part of a URL, every other byte is escaped and "#" starts the fragment.
+// They are ordered by how readable they are in an example path.
+const pathAlphabet = "abcdefghijklmnopqrstuvwxyz0123456789/.-_~ABCDEFGHIJKLMNOPQRSTUVWXYZ%?=&+,;:@!$'()*[]^`{|}\\\"<>"
+
+const (
+	stepByte        = iota // The pattern goes on with a byte.
+	stepStar               // The pattern goes on with "*", any number of any bytes.
+	stepEnd                // The pattern matched, whatever follows in the path.
+	stepAnchoredEnd        // The pattern matched if the path ends here, it ended with "$".
+)
+
+/*
+automaton matches paths against every allow and disallow directive of a group at once. A pattern is made of bytes that only match
+themselves, "*" that matches any number of any bytes, and a "$" at its very end that makes the pattern match whole paths instead of
+their start. A "$" anywhere else is a byte like any other:
+
+	Disallow: /cms/        -> "/cms/"
+	Allow: /*.pdf$         -> "/", "*", ".pdf", and the end of the path
+	Disallow: /a$b         -> "/a$b"
+
+Every pattern is a chain of steps, one for every byte or "*" of the pattern and one for its end. A state is the steps every pattern
+can be at after reading a path, so two paths that end in the same state are matched by the same directives no matter what follows
+them. States are only built when a path first needs them and kept for the next path, which makes matching a path read each of its
+bytes once no matter how many directives there are.
+
+There are only so many states, which makes walking every one of them a complete answer for the infinite number of paths. The path
+that first reaches a state is the shortest one, it makes an example of everything that is true for the state. Paths never start
+with "//" because a URL reads what follows it as a host.
+
+An automaton is safe for concurrent use.
+*/
+type automaton struct {
+	rules []Rule
+	steps []automatonStep
+	first []int32 // The first step of every directive.
+
+	// classes numbers the bytes by the transitions they take, bytes that are not part of any pattern all take the same ones and
+	// share class 0. "/" always has a class of its own because no path starts with "//".
+	classes    [256]uint16
+	classCount int
+
+	// alphabet is one byte of pathAlphabet for every class that has one, the paths that explore builds only need those.
+	alphabet []byte
+
+	// limit is how many states are kept, states after that are built again every time they are needed. There is no limit when
+	// zero.
+	limit int
+
+	mu      sync.RWMutex
+	initial *automatonState
+	states  map[string]*automatonState
+}
+
+type automatonStep struct {
+	rule int
+	kind int
+	b    byte
+}
+
+// automatonState is where the patterns are after reading a path.
+type automatonState struct {
+	steps []int32 // Sorted, a directive that matched whatever follows only keeps its end.
+	next  []*automatonState
+
+	// allow and disallow are the longest allow and disallow directives that match when the path ends here, -1 when there is none.
+	allow, disallow int
+
+	// settled is set when no directive can change between matching and not matching after the state.
+	settled bool
+
+	// kept is set when the automaton keeps the state, only kept states remember their transitions.
+	kept bool
+}
+
+func newAutomaton(rules []Rule) *automaton {
+	automaton := &automaton{rules: rules, first: make([]int32, len(rules)), states: make(map[string]*automatonState)}
+	automaton.classCount++
+	automaton.classes['/'] = uint16(automaton.classCount)
+	for i, rule := range rules {
+		automaton.first[i] = int32(len(automaton.steps))
+		pattern, anchored := parsePattern(rule.Path)
+		for j := 0; j < len(pattern); j++ {
+			step := automatonStep{rule: i, kind: stepByte, b: pattern[j]}
+			if pattern[j] == '*' {
+				step.kind = stepStar
+			} else if automaton.classes[pattern[j]] == 0 {
+				automaton.classCount++
+				automaton.classes[pattern[j]] = uint16(automaton.classCount)
+			}
+			automaton.steps = append(automaton.steps, step)
+		}
+		end := automatonStep{rule: i, kind: stepEnd}
+		if anchored {
+			end.kind = stepAnchoredEnd
+		}
+		automaton.steps = append(automaton.steps, end)
+	}
+	automaton.classCount++
+
+	represented := make([]bool, automaton.classCount)
+	for i := 0; i < len(pathAlphabet); i++ {
+		if class := automaton.classes[pathAlphabet[i]]; !represented[class] {
+			represented[class] = true
+			automaton.alphabet = append(automaton.alphabet, pathAlphabet[i])
+		}
+	}
+
+	var steps []int32
+	for _, first := range automaton.first {
+		steps = automaton.closure(steps, first)
+	}
+	automaton.initial = automaton.state(automaton.normalize(steps))
+	return automaton
+}
+
+// parsePattern returns the bytes and stars a pattern has to match and whether it has to match the whole path. Stars in a row are
+// the same as one and a star at the end of a pattern is the same as none.
+func parsePattern(path string) (string, bool) {
+	anchored := strings.HasSuffix(path, "$")
+	if anchored {
+		path = path[:len(path)-1]
+	}
+	for strings.Contains(path, "**") {
+		path = strings.Replace(path, "**", "*", -1)
+	}
+	if strings.HasSuffix(path, "*") {
+		return path[:len(path)-1], false
+	}
+	return path, anchored
+}
+
+// start returns the state for the path "/", every path starts with it.
+func (automaton *automaton) start() *automatonState {
+	return automaton.next(automaton.initial, '/')
+}
+
+// walk returns the state after reading a path from the start, the path has to start with "/".
+func (automaton *automaton) walk(path string) *automatonState {
+	return automaton.walkFrom(automaton.initial, path)
+}
+
+// walkFrom returns the state after reading a path from a state.
+func (automaton *automaton) walkFrom(state *automatonState, path string) *automatonState {
+	automaton.mu.RLock()
+	for i := 0; i < len(path) && !state.settled; i++ {
+		next := state.next[automaton.classes[path[i]]]
+		if next == nil {
+			automaton.mu.RUnlock()
+			next = automaton.follow(state, path[i])
+			automaton.mu.RLock()
+		}
+		state = next
+	}
+	automaton.mu.RUnlock()
+	return state
+}
+
+// next returns the state after reading one more byte.
+func (automaton *automaton) next(state *automatonState, b byte) *automatonState {
+	automaton.mu.RLock()
+	next := state.next[automaton.classes[b]]
+	automaton.mu.RUnlock()
+	if next != nil {
+		return next
+	}
+	return automaton.follow(state, b)
+}
+
+// follow builds the state after reading a byte and remembers it as the transition of the state.
+func (automaton *automaton) follow(state *automatonState, b byte) *automatonState {
+	var steps []int32
+	for _, step := range state.steps {
+		switch automaton.steps[step].kind {
+		case stepByte:
+			if automaton.steps[step].b == b {
+				steps = automaton.closure(steps, step+1)
+			}
+		case stepStar:
+			steps = automaton.closure(steps, step)
+		case stepEnd:
+			steps = append(steps, step)
+		}
+	}
+	steps = automaton.normalize(steps)
+
+	automaton.mu.Lock()
+	defer automaton.mu.Unlock()
+	next := automaton.state(steps)
+	if next.kept && state.kept {
+		state.next[automaton.classes[b]] = next
+	}
+	return next
+}
+
+// successors returns the states after reading a byte of every class, indexed by class. It reads the steps of the state once for all
+// of them, which is what explore needs instead of following one byte at a time.
+func (automaton *automaton) successors(state *automatonState) []*automatonState {
+	automaton.mu.RLock()
+	complete := true
+	for _, next := range state.next {
+		complete = complete && next != nil
+	}
+	automaton.mu.RUnlock()
+	if complete {
+		return state.next
+	}
+
+	// The steps that go on whatever byte is read and the steps that only go on with a byte of their class.
+	var common []int32
+	byClass := make([][]int32, automaton.classCount)
+	for _, step := range state.steps {
+		switch automaton.steps[step].kind {
+		case stepByte:
+			class := automaton.classes[automaton.steps[step].b]
+			byClass[class] = automaton.closure(byClass[class], step+1)
+		case stepStar:
+			common = automaton.closure(common, step)
+		case stepEnd:
+			common = append(common, step)
+		}
+	}
+	// Most classes have no step of their own and share the state of the common steps.
+	common = automaton.normalize(common)
+	for class := range byClass {
+		if len(byClass[class]) > 0 {
+			byClass[class] = automaton.normalize(append(append([]int32{}, common...), byClass[class]...))
+		}
+	}
+
+	automaton.mu.Lock()
+	defer automaton.mu.Unlock()
+	commonState := automaton.state(common)
+	successors := make([]*automatonState, automaton.classCount)
+	for class, steps := range byClass {
+		successors[class] = commonState
+		if len(steps) > 0 {
+			successors[class] = automaton.state(steps)
+		}
+		if state.kept && successors[class].kept {
+			state.next[class] = successors[class]
+		}
+	}
+	return successors
+}
+
+// state returns the kept state for the steps or a new one, the new state is kept while there is room. The caller must hold the
+// lock unless nothing else can use the automaton yet.
+func (automaton *automaton) state(steps []int32) *automatonState {
+	key := automaton.key(steps)
+	if state, exists := automaton.states[key]; exists {
+		return state
+	}
+
+	state := &automatonState{steps: steps, next: make([]*automatonState, automaton.classCount), allow: -1, disallow: -1, settled: true}
+	for _, step := range steps {
+		kind := automaton.steps[step].kind
+		if kind != stepEnd {
+			state.settled = false
+		}
+		if kind != stepEnd && kind != stepAnchoredEnd {
+			continue
+		}
+		rule := automaton.steps[step].rule
+		longest := &state.disallow
+		if automaton.rules[rule].Allow {
+			longest = &state.allow
+		}
+		if *longest == -1 || len(automaton.rules[rule].Path) > len(automaton.rules[*longest].Path) {
+			*longest = rule
+		}
+	}
+	if automaton.limit == 0 || len(automaton.states) < automaton.limit {
+		state.kept = true
+		automaton.states[key] = state
+	}
+	return state
+}
+
+// closure adds a step and the steps that follow it without reading a byte, which are the steps after a "*".
+func (automaton *automaton) closure(steps []int32, step int32) []int32 {
+	steps = append(steps, step)
+	for automaton.steps[step].kind == stepStar {
+		step++
+		steps = append(steps, step)
+	}
+	return steps
+}
+
+// normalize sorts the steps and drops duplicates as well as the other steps of a directive that matched whatever follows.
+func (automaton *automaton) normalize(steps []int32) []int32 {
+	// Following the sorted steps of a state mostly gives sorted steps already.
+	sorted := true
+	for i := 1; i < len(steps) && sorted; i++ {
+		sorted = steps[i-1] <= steps[i]
+	}
+	if !sorted {
+		sort.Slice(steps, func(i, j int) bool {
+			return steps[i] < steps[j]
+		})
+	}
+	// The end of a directive comes after its other steps, so walking backwards finds it first.
+	normalized := make([]int32, 0, len(steps))
+	ended := -1
+	for i := len(steps) - 1; i >= 0; i-- {
+		step := automaton.steps[steps[i]]
+		if (i < len(steps)-1 && steps[i] == steps[i+1]) || step.rule == ended {
+			continue
+		}
+		if step.kind == stepEnd {
+			ended = step.rule
+		}
+		normalized = append(normalized, steps[i])
+	}
+	for i, j := 0, len(normalized)-1; i < j; i, j = i+1, j-1 {
+		normalized[i], normalized[j] = normalized[j], normalized[i]
+	}
+	return normalized
+}
+
+func (automaton *automaton) key(steps []int32) string {
+	key := make([]byte, 4*len(steps))
+	for i, step := range steps {
+		binary.LittleEndian.PutUint32(key[4*i:], uint32(step))
+	}
+	return string(key)
+}
+
+// matches returns the positions of the directives that match the path of the state when the path ends there, in order.
+func (automaton *automaton) matches(state *automatonState) []int {
+	var matches []int
+	for _, step := range state.steps {
+		if kind := automaton.steps[step].kind; kind == stepEnd || kind == stepAnchoredEnd {
+			matches = append(matches, automaton.steps[step].rule)
+		}
+	}
+	return matches
+}
+
+// explore visits every state shortest path first until visit returns false, starting with a state and the path that reaches it.
+// States that all paths through them end the same way as the state itself are not followed.
+//
+// Patterns without stars never need more states than they have steps, but every pattern like "/*a*d*h" multiplies the states of
+// the others. explore returns ErrTooComplex once there are maxIndexStates more states than steps instead of taking forever.
+func (automaton *automaton) explore(from *automatonState, path string, visit func(state *automatonState, path string) bool) error {
+	type queued struct {
+		state *automatonState
+		path  string
+	}
+	// The state of "/" can be reached again by a path that can go on with "/", so it is only seen once it is reached that way.
+	seen := map[*automatonState]bool{from: path != "/"}
+	queue := []queued{{from, path}}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if !visit(current.state, current.path) {
+			return nil
+		}
+		if current.state.settled {
+			continue
+		}
+		// Bytes of the same class lead to the same state, so the first byte of every class is enough.
+		successors := automaton.successors(current.state)
+		automaton.mu.RLock()
+		states := len(automaton.states)
+		automaton.mu.RUnlock()
+		if states > maxIndexStates+len(automaton.steps) {
+			return ErrTooComplex
+		}
+		for _, b := range automaton.alphabet {
+			if current.path == "/" && b == '/' {
+				// A URL reads what follows "//" as a host, so no path starts with it.
+				continue
+			}
+			next := successors[automaton.classes[b]]
+			if !seen[next] {
+				seen[next] = true
+				queue = append(queue, queued{next, current.path + string(b)})
+			}
+		}
+	}
+	return nil
+}
+
+// decide returns whether the directives of a group allow a path that the matched directives match together with the position of
+// the directive that decides it, -1 when no directive matches. It follows the same rules as CanCrawl, the longest directive wins
+// and allow wins a tie.
+func decide(rules []Rule, matched []int) (bool, int) {
+	allow, disallow := -1, -1
+	for _, i := range matched {
+		rule := rules[i]
+		if rule.Allow && (allow == -1 || len(rule.Path) > len(rules[allow].Path)) {
+			allow = i
+		}
+		if !rule.Allow && (disallow == -1 || len(rule.Path) > len(rules[disallow].Path)) {
+			disallow = i
+		}
+	}
+	if disallow != -1 && (allow == -1 || len(rules[disallow].Path) > len(rules[allow].Path)) {
+		return false, disallow
+	}
+	return true, allow
+}
@@ -0,0 +1,93 @@
+package robotstxt
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+)
+
+func TestAutomaton_matches_agrees_with_patterns(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	pattern := func() string {
+		path := "/"
+		if random.Intn(5) == 0 {
+			path = "*"
+		}
+		for i := random.Intn(6); i >= 0; i-- {
+			path += string(`ab/.*?^\`[random.Intn(8)])
+		}
+		if random.Intn(4) == 0 {
+			path += "$"
+		}
+		return path
+	}
+
+	for round := 0; round < 200; round++ {
+		var rules []Rule
+		for i := random.Intn(10); i >= 0; i-- {
+			rules = append(rules, Rule{Allow: random.Intn(2) == 0, Path: pattern()})
+		}
+		automaton := newAutomaton(rules)
+
+		for i := 0; i < 50; i++ {
+			path := "/"
+			for j := random.Intn(8); j > 0; j-- {
+				path += string("ab/.?^"[random.Intn(6)])
+			}
+			matched := make(map[int]bool)
+			for _, rule := range automaton.matches(automaton.walk(path)) {
+				matched[rule] = true
+			}
+			for j, rule := range rules {
+				assert.Equal(t, patternMatches(rule.Path, path), matched[j], "%s %s", path, rule.Path)
+			}
+		}
+	}
+}
+
+func TestAutomaton_explore(t *testing.T) {
+	rules := []Rule{{Path: "/cms/"}, {Allow: true, Path: "/*.pdf$"}, {Path: "/a\\bc*"}}
+	automaton := newAutomaton(rules)
+	examples := make(map[[3]bool]string)
+	err := automaton.explore(automaton.start(), "/", func(state *automatonState, path string) bool {
+		var key [3]bool
+		for _, rule := range automaton.matches(state) {
+			key[rule] = true
+		}
+		if _, exists := examples[key]; !exists {
+			examples[key] = path
+		}
+		return true
+	})
+	assert.Nil(t, err)
+	// A "." and a "\" only match themselves.
+	assert.Equal(t, map[[3]bool]string{
+		{false, false, false}: "/",
+		{true, false, false}:  "/cms/",
+		{false, true, false}:  "/.pdf",
+		{true, true, false}:   "/cms/.pdf",
+		{false, false, true}:  "/a\\bc",
+		{false, true, true}:   "/a\\bc.pdf",
+	}, examples)
+
+	// The longest directive that matches decides.
+	allowed, rule := decide(rules, automaton.matches(automaton.walk("/cms/file.pdf")))
+	assert.True(t, allowed)
+	assert.Equal(t, 1, rule)
+	allowed, rule = decide(rules, automaton.matches(automaton.walk("/cms/file.pdf?download")))
+	assert.False(t, allowed)
+	assert.Equal(t, 0, rule)
+	allowed, rule = decide(rules, automaton.matches(automaton.walk("/products/")))
+	assert.True(t, allowed)
+	assert.Equal(t, -1, rule)
+}
+
+func TestPathAlphabet(t *testing.T) {
+	for b := byte(0x21); b < 0x7f; b++ {
+		if b == '#' {
+			continue
+		}
+		assert.Contains(t, pathAlphabet, string(b))
+	}
+	assert.Len(t, pathAlphabet, 0x7f-0x21-1)
+}
@@ -0,0 +1,150 @@
+package robotstxt
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+Builder creates a RobotsTxt from code instead of parsing one, i.e. to generate the robots.txt of a site:
+
+	builder := &robotstxt.Builder{}
+	builder.Comment("Robots.txt for dumpsters.com")
+	builder.AddGroup("*").CrawlDelay(5 * time.Second).Disallow("/cms/", "/pricing/admin/").Allow("/cms/public/")
+	builder.AddGroup("AdsBot-Google").Comment("Ads are checked everywhere").Allow("/")
+	builder.Sitemap("https://www.dumpsters.com/sitemap.xml")
+
+	robotsTxt, err := builder.Build("https://www.dumpsters.com")
+	fmt.Print(robotsTxt)
+
+Crawl delays are rounded up to whole seconds. Nothing is checked until Build, which fails when an agent, path, or sitemap could not be
+written to or read back from a robots.txt. The zero value is ready to use.
+*/
+type Builder struct {
+	comment  string
+	groups   []*GroupBuilder
+	sitemaps []string
+}
+
+// GroupBuilder adds directives to a single group of a Builder.
+type GroupBuilder struct {
+	group Group
+}
+
+// Comment adds a line to the comment at the top of the robots.txt.
+func (builder *Builder) Comment(comment string) *Builder {
+	builder.comment = joinComment(builder.comment, comment)
+	return builder
+}
+
+// AddGroup starts a new group for the robots (user-agents).
+func (builder *Builder) AddGroup(agents ...string) *GroupBuilder {
+	groupBuilder := &GroupBuilder{group: Group{Agents: append([]string(nil), agents...)}}
+	builder.groups = append(builder.groups, groupBuilder)
+	return groupBuilder
+}
+
+// Sitemap adds the absolute URL of a sitemap.
+func (builder *Builder) Sitemap(url string) *Builder {
+	builder.sitemaps = append(builder.sitemaps, url)
+	return builder
+}
+
+// Build creates the RobotsTxt for a URL, see New.
+func (builder *Builder) Build(url string) (*RobotsTxt, error) {
+	draft := &RobotsTxt{comment: builder.comment, sitemaps: builder.sitemaps}
+	for _, groupBuilder := range builder.groups {
+		err := validateGroup(groupBuilder.group)
+		if err != nil {
+			return &RobotsTxt{}, err
+		}
+		draft.groups = append(draft.groups, groupBuilder.group)
+	}
+	for _, sitemap := range builder.sitemaps {
+		_, err := normalizeUrl(sitemap)
+		if err != nil || !validValue(sitemap) {
+			return &RobotsTxt{}, errors.New("invalid sitemap " + strconv.Quote(sitemap) + ", it must be an absolute URL")
+		}
+	}
+
+	// Parsing what is written guarantees that the RobotsTxt behaves exactly like the robots.txt it writes.
+	robotsTxt, err := parse(url, strings.NewReader(draft.String()))
+	if err != nil {
+		return &RobotsTxt{}, err
+	}
+	robotsTxt.comment = draft.comment
+	for i := range robotsTxt.groups {
+		robotsTxt.groups[i].Comment = draft.groups[i].Comment
+	}
+	return robotsTxt, nil
+}
+
+// Comment adds a line to the comment above the group.
+func (groupBuilder *GroupBuilder) Comment(comment string) *GroupBuilder {
+	groupBuilder.group.Comment = joinComment(groupBuilder.group.Comment, comment)
+	return groupBuilder
+}
+
+// Allow adds an allow directive for every path.
+func (groupBuilder *GroupBuilder) Allow(paths ...string) *GroupBuilder {
+	for _, path := range paths {
+		groupBuilder.group.Rules = append(groupBuilder.group.Rules, Rule{Allow: true, Path: path})
+	}
+	return groupBuilder
+}
+
+// Disallow adds a disallow directive for every path.
+func (groupBuilder *GroupBuilder) Disallow(paths ...string) *GroupBuilder {
+	for _, path := range paths {
+		groupBuilder.group.Rules = append(groupBuilder.group.Rules, Rule{Path: path})
+	}
+	return groupBuilder
+}
+
+// CrawlDelay sets how long the robots of the group wait between accessing pages.
+func (groupBuilder *GroupBuilder) CrawlDelay(crawlDelay time.Duration) *GroupBuilder {
+	groupBuilder.group.CrawlDelay = crawlDelay
+	return groupBuilder
+}
+
+func validateGroup(group Group) error {
+	if len(group.Agents) == 0 {
+		return errors.New("a group needs at least one user agent")
+	}
+	for _, agent := range group.Agents {
+		if !validValue(agent) {
+			return errors.New("invalid user agent " + strconv.Quote(agent))
+		}
+	}
+	if group.CrawlDelay < 0 {
+		return errors.New("invalid crawl delay " + group.CrawlDelay.String() + ", it can not be negative")
+	}
+	for _, rule := range group.Rules {
+		err := validateRule(rule)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateRule(rule Rule) error {
+	if !validValue(rule.Path) || !(strings.HasPrefix(rule.Path, "/") || strings.HasPrefix(rule.Path, "*")) {
+		return &PatternError{Pattern: rule.Path, Err: errors.New("a path must start with \"/\" or \"*\" and can not contain spaces or \"#\"")}
+	}
+	return nil
+}
+
+// validValue reports whether a value survives being written to and parsed from a robots.txt.
+func validValue(value string) bool {
+	return value != "" && validateUTF8(value) && !strings.ContainsAny(value, " \t\r\n#")
+}
+
+func joinComment(comment, line string) string {
+	if comment == "" {
+		return line
+	}
+	return comment + "\n" + line
+}
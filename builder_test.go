@@ -0,0 +1,180 @@
+package robotstxt_test
+
+import (
+	"errors"
+	"fmt"
+	"github.com/itmayziii/robotstxt/v2"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuilder_Build(t *testing.T) {
+	builder := &robotstxt.Builder{}
+	builder.Comment("Robots.txt for dumpsters.com").Comment("Generated, do not edit")
+	builder.AddGroup("*").CrawlDelay(1500*time.Millisecond).Disallow("/cms/", "/pricing/admin/").Allow("/cms/public/")
+	builder.AddGroup("AdsBot-Google", "AdsBot-Bing").Comment("Ads are checked everywhere")
+	builder.Sitemap("https://www.dumpsters.com/sitemap.xml")
+
+	robotsTxt, err := builder.Build("https://www.dumpsters.com")
+	assert.Nil(t, err)
+	assert.Equal(t, `# Robots.txt for dumpsters.com
+# Generated, do not edit
+
+User-agent: *
+Crawl-delay: 2
+Disallow: /cms/
+Disallow: /pricing/admin/
+Allow: /cms/public/
+
+# Ads are checked everywhere
+User-agent: AdsBot-Google
+User-agent: AdsBot-Bing
+Disallow:
+
+Sitemap: https://www.dumpsters.com/sitemap.xml
+`, robotsTxt.String())
+
+	testRobot(t, "googlebot", robotsTxt, []testUrl{
+		{url: "/cms/pages", crawlable: false, hasError: false},
+		{url: "/cms/public/pages", crawlable: true, hasError: false},
+		{url: "/products/", crawlable: true, hasError: false},
+	})
+	testRobot(t, "adsbot-bing", robotsTxt, []testUrl{
+		{url: "/cms/pages", crawlable: true, hasError: false},
+	})
+	assert.Equal(t, 2*time.Second, robotsTxt.CrawlDelay("googlebot"))
+	assert.Equal(t, []string{"https://www.dumpsters.com/sitemap.xml"}, robotsTxt.Sitemaps())
+	assert.Equal(t, []robotstxt.Group{
+		{
+			Agents:     []string{"*"},
+			Rules:      []robotstxt.Rule{{Path: "/cms/"}, {Path: "/pricing/admin/"}, {Allow: true, Path: "/cms/public/"}},
+			CrawlDelay: 2 * time.Second,
+		},
+		{Agents: []string{"AdsBot-Google", "AdsBot-Bing"}, Comment: "Ads are checked everywhere"},
+	}, robotsTxt.Groups())
+}
+
+func TestBuilder_Build_rejects_what_can_not_be_written(t *testing.T) {
+	builds := map[string]func(builder *robotstxt.Builder){
+		"no agents":       func(builder *robotstxt.Builder) { builder.AddGroup().Disallow("/") },
+		"agent with body": func(builder *robotstxt.Builder) { builder.AddGroup("dumpster bot").Disallow("/") },
+		"relative path":   func(builder *robotstxt.Builder) { builder.AddGroup("*").Disallow("cms/") },
+		"commented path":  func(builder *robotstxt.Builder) { builder.AddGroup("*").Disallow("/cms/#top") },
+		"negative delay":  func(builder *robotstxt.Builder) { builder.AddGroup("*").CrawlDelay(-time.Second) },
+		"relative sitemap": func(builder *robotstxt.Builder) {
+			builder.AddGroup("*").Disallow("/")
+			builder.Sitemap("/sitemap.xml")
+		},
+	}
+	for name, build := range builds {
+		builder := &robotstxt.Builder{}
+		build(builder)
+		_, err := builder.Build("https://www.dumpsters.com")
+		assert.NotNil(t, err, name)
+	}
+
+	builder := &robotstxt.Builder{}
+	builder.AddGroup("*").Disallow("cms/")
+	_, err := builder.Build("https://www.dumpsters.com")
+	var patternError *robotstxt.PatternError
+	assert.True(t, errors.As(err, &patternError))
+	assert.Equal(t, "cms/", patternError.Pattern)
+}
+
+func TestNew_empty_disallow_ends_the_user_agents_of_a_group(t *testing.T) {
+	robotsTxt, err := robotstxt.New("https://www.dumpsters.com", strings.NewReader(`
+User-agent: AdsBot-Google
+Disallow:
+
+User-agent: *
+Disallow: /
+`))
+	assert.Nil(t, err)
+
+	testRobot(t, "AdsBot-Google", robotsTxt, []testUrl{{url: "/cms/", crawlable: true, hasError: false}})
+	testRobot(t, "googlebot", robotsTxt, []testUrl{{url: "/cms/", crawlable: false, hasError: false}})
+}
+
+func TestRobotsTxt_WriteTo_round_trip(t *testing.T) {
+	robotsTxt, err := robotstxt.New("https://www.dumpsters.com", getExampleRobotsTxt())
+	assert.Nil(t, err)
+	assertRoundTrip(t, robotsTxt, []string{"googlebot", "adsbot-google", "adsbot-bing"}, []string{
+		"/cms/pages", "/pricing?cart=full&s=lightbox", "/se/en", "/store/retail/online/frontend/", "/be/fr_fr/retail/fr/frontend/",
+	})
+
+	var builder strings.Builder
+	n, err := robotsTxt.WriteTo(&builder)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(builder.Len()), n)
+}
+
+// Any robots.txt that can be built reads back the same.
+func TestRobotsTxt_WriteTo_round_trip_random(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	agents := []string{"*", "googlebot", "googlebot-news", "bingbot", "dumpsterbot"}
+	paths := []string{"/", "/cms/", "/cms/public/", "/*.pdf$", "/pricing", "*?s=lightbox", "/se/en$", "/*/frontend/"}
+	urls := []string{"/", "/cms/", "/cms/public/brochure.pdf", "/pricing?s=lightbox", "/se/en", "/store/frontend/", "/products/"}
+
+	for round := 0; round < 100; round++ {
+		builder := &robotstxt.Builder{}
+		for groups := random.Intn(4); groups >= 0; groups-- {
+			group := builder.AddGroup(agents[random.Intn(len(agents))])
+			if random.Intn(3) == 0 {
+				group = builder.AddGroup(agents[random.Intn(len(agents))], agents[random.Intn(len(agents))])
+			}
+			for rules := random.Intn(4); rules > 0; rules-- {
+				if random.Intn(2) == 0 {
+					group.Allow(paths[random.Intn(len(paths))])
+				} else {
+					group.Disallow(paths[random.Intn(len(paths))])
+				}
+			}
+			if random.Intn(3) == 0 {
+				group.CrawlDelay(time.Duration(random.Intn(10)) * time.Second)
+			}
+		}
+		if random.Intn(2) == 0 {
+			builder.Sitemap(fmt.Sprintf("https://www.dumpsters.com/sitemap-%d.xml", round))
+		}
+
+		robotsTxt, err := builder.Build("https://www.dumpsters.com")
+		assert.Nil(t, err)
+		assertRoundTrip(t, robotsTxt, agents, urls)
+	}
+}
+
+func assertRoundTrip(t *testing.T, robotsTxt *robotstxt.RobotsTxt, agents, urls []string) {
+	reparsed, err := robotstxt.New(robotsTxt.URL(), strings.NewReader(robotsTxt.String()))
+	assert.Nil(t, err)
+
+	groups := robotsTxt.Groups()
+	for i := range groups {
+		groups[i].Comment = ""
+	}
+	assert.Equal(t, groups, reparsed.Groups(), robotsTxt.String())
+	assert.Equal(t, robotsTxt.Sitemaps(), reparsed.Sitemaps())
+	for _, agent := range agents {
+		assert.Equal(t, robotsTxt.CrawlDelay(agent), reparsed.CrawlDelay(agent))
+		assert.Equal(t, robotsTxt.CanCrawlBatch(agent, urls), reparsed.CanCrawlBatch(agent, urls), agent)
+	}
+}
+
+func ExampleBuilder() {
+	builder := &robotstxt.Builder{}
+	builder.AddGroup("*").Disallow("/cms/").Allow("/cms/public/")
+	builder.Sitemap("https://www.dumpsters.com/sitemap.xml")
+
+	robotsTxt, err := builder.Build("https://www.dumpsters.com")
+	fmt.Println(err)
+	fmt.Print(robotsTxt)
+	// Output:
+	// <nil>
+	// User-agent: *
+	// Disallow: /cms/
+	// Allow: /cms/public/
+	//
+	// Sitemap: https://www.dumpsters.com/sitemap.xml
+}
@@ -0,0 +1,570 @@
+package robotstxt
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultCacheTTL is how long a robots.txt is cached when the response does not say otherwise.
+	defaultCacheTTL = 24 * time.Hour
+	// maxCacheTTL is the longest a robots.txt should be cached according to RFC 9309,
+	// https://www.rfc-editor.org/rfc/rfc9309.html#section-2.4.
+	maxCacheTTL = 24 * time.Hour
+	// maxStaleIfError is the longest an unreachable robots.txt is served from a stale copy, RFC 9309 uses 30 days as an example of a
+	// reasonably long period, https://www.rfc-editor.org/rfc/rfc9309.html#section-2.3.1.4.
+	maxStaleIfError = 30 * 24 * time.Hour
+	// defaultErrorRetryInterval is how long the result of a failed fetch is reused before the robots.txt is fetched again.
+	defaultErrorRetryInterval = time.Minute
+	// maxErrorRetryInterval caps the retry interval, which doubles with every failed fetch in a row.
+	maxErrorRetryInterval = time.Hour
+	// defaultCacheMaxEntries is how many parsed entries a Cache keeps in memory when MaxEntries is not set.
+	defaultCacheMaxEntries = 1000
+)
+
+// FallbackPolicy decides what a Cache does when a robots.txt is unreachable and there is no usable stale copy.
+type FallbackPolicy int
+
+const (
+	// FallbackError returns the error that happened while fetching the robots.txt.
+	FallbackError FallbackPolicy = iota
+	// FallbackDisallowAll treats the site as fully disallowed, which is what RFC 9309 asks for when a robots.txt is unreachable.
+	FallbackDisallowAll
+	// FallbackAllowAll treats the site as if it did not have a robots.txt.
+	FallbackAllowAll
+)
+
+var timeNow = time.Now
+
+/*
+Cache stores a RobotsTxt per origin so that the robots.txt for a host is only fetched again once it expires. URLs are normalized to
+their origin the same way RobotsTxt.URL is, so all of the following share one cache entry:
+
+	https://www.dumpsters.com
+	https://www.dumpsters.com:443/pricing/roll-off-dumpsters
+	https://www.dumpsters.com/robots.txt
+
+The lifetime of an entry comes from the "Cache-Control: max-age" or "Expires" response headers and defaults to 24 hours, it is
+never longer than MaxTTL. Concurrent lookups for an origin that is not cached share a single fetch, when the caller that started
+it gives up the others fetch again instead of failing with its error.
+
+Expired entries that were served with an "ETag" or "Last-Modified" header are revalidated with a conditional request, a
+"304 Not Modified" response extends the lifetime of the entry without parsing the robots.txt again.
+
+When a robots.txt becomes unreachable, the server responds with a 5xx or there is no response at all, the last good copy keeps
+being served for StaleIfError after it expired. Once that window passes, or when there never was a good copy, FallbackPolicy decides
+what happens. Other errors, i.e. a body that can not be parsed or a redirect that is not followed, are always returned.
+
+The result of a failed fetch, a stale copy, the fallback, or the error, is reused for ErrorRetryInterval before the robots.txt is
+fetched again. The interval doubles with every failed fetch in a row up to an hour, so a host that is down is not asked for its
+robots.txt on every lookup.
+
+Entries are kept in Store, which holds the raw body of every robots.txt so entries can be parsed again by newer versions of this
+package, i.e. after a restart when a FileStore is used. The MaxEntries most recently used entries are also kept in memory parsed so
+a hit does not read the Store, the others are read from the Store and parsed again when they are needed. An entry that can not be
+read from the Store or no longer parses is a miss that is fetched again and overwritten, and a Store that can not write an entry
+does not fail the lookup, both are counted in CacheStats.StoreErrors.
+
+The zero value is ready to use and a Cache is safe for concurrent use.
+*/
+type Cache struct {
+	// Fetcher retrieves robots.txt files that are not cached, a zero Fetcher is used when nil.
+	Fetcher *Fetcher
+
+	// Store keeps the cached entries, a MemoryStore is used when nil.
+	Store Store
+
+	// DefaultTTL is used when the response does not declare a lifetime, 24 hours is used when zero.
+	DefaultTTL time.Duration
+
+	// MaxTTL caps the lifetime of every entry, 24 hours is used when zero.
+	MaxTTL time.Duration
+
+	// StaleIfError is how long an expired entry can still be served while the robots.txt is unreachable, it is capped at 30 days.
+	// Stale entries are never served when zero.
+	StaleIfError time.Duration
+
+	// FallbackPolicy is used when the robots.txt is unreachable and there is no entry that can be served, errors are returned by
+	// default.
+	FallbackPolicy FallbackPolicy
+
+	// ErrorRetryInterval is how long the result of a failed fetch is reused before fetching again, 1 minute is used when zero.
+	ErrorRetryInterval time.Duration
+
+	// MaxEntries is the maximum number of parsed entries, and of failed fetches, kept in memory, 1000 is used when zero.
+	MaxEntries int
+
+	mu          sync.Mutex
+	memoryStore MemoryStore
+	entries     map[string]*list.Element // The parsed entries of the Store that were used most recently.
+	lru         *list.List
+	inFlight    flightGroup
+	failures    map[string]*cacheFailure
+	stats       CacheStats
+}
+
+// cacheFailure is the result of the last fetch of an origin that failed.
+type cacheFailure struct {
+	entry   *CacheEntry
+	err     error
+	retryAt time.Time
+
+	// failures is the number of fetches in a row that failed.
+	failures int
+}
+
+// CacheStats counts how lookups were answered by a Cache.
+type CacheStats struct {
+	// Hits is the number of lookups answered by a fresh entry.
+	Hits uint64
+
+	// Misses is the number of lookups for an origin that was not cached or whose entry could not be revalidated.
+	Misses uint64
+
+	// Revalidations is the number of lookups for an expired entry that were answered with a conditional request.
+	Revalidations uint64
+
+	// NotModified is the number of revalidations that resulted in a "304 Not Modified" response.
+	NotModified uint64
+
+	// Stale is the number of lookups answered by a stale entry because the robots.txt was unreachable.
+	Stale uint64
+
+	// Fallbacks is the number of lookups answered by the FallbackPolicy because the robots.txt was unreachable.
+	Fallbacks uint64
+
+	// StoreErrors is the number of entries the Store could not read, write, or that could not be parsed again.
+	StoreErrors uint64
+}
+
+// CacheEntry is a cached robots.txt along with when it was fetched and when it expires.
+type CacheEntry struct {
+	// Origin is the normalized origin the entry belongs to, i.e. https://www.dumpsters.com:443.
+	Origin string
+
+	// RobotsTxt holds the parsed rules, it is nil for entries that come straight out of a Store that only keeps the raw body.
+	RobotsTxt *RobotsTxt
+
+	// URL is the robots.txt URL that was requested.
+	URL string
+
+	// FinalURL is the URL that served the robots.txt after following every redirect.
+	FinalURL string
+
+	// Redirects is the redirect chain that was followed to get to FinalURL.
+	Redirects []Redirect
+
+	// StatusCode is the HTTP status code the robots.txt was served with.
+	StatusCode int
+
+	// Body is the raw robots.txt as it was served.
+	Body []byte
+
+	// FetchedAt is when the robots.txt was retrieved.
+	FetchedAt time.Time
+
+	// Expires is when the entry needs to be fetched again.
+	Expires time.Time
+
+	// ETag is the "ETag" header of the response, it is sent as "If-None-Match" when the entry is revalidated.
+	ETag string
+
+	// LastModified is the "Last-Modified" header of the response, it is sent as "If-Modified-Since" when the entry is revalidated.
+	LastModified string
+
+	// Stale is set when the entry expired but is served anyway because the robots.txt is unreachable.
+	Stale bool
+
+	// Fallback is set when the RobotsTxt did not come from the site but from the FallbackPolicy of the Cache.
+	Fallback bool
+}
+
+// Fresh reports whether the entry can still be used at the given time.
+func (cacheEntry *CacheEntry) Fresh(now time.Time) bool {
+	return now.Before(cacheEntry.Expires)
+}
+
+// Get returns the RobotsTxt for the origin of the URL, it is only fetched when it is not cached or the cached copy expired.
+func (cache *Cache) Get(ctx context.Context, url string) (*RobotsTxt, error) {
+	cacheEntry, err := cache.GetEntry(ctx, url)
+	if err != nil {
+		return &RobotsTxt{}, err
+	}
+	return cacheEntry.RobotsTxt, nil
+}
+
+// GetEntry is the same as Get but also returns when the robots.txt was fetched, when it expires, and whether the decisions it makes
+// come from a stale copy or the fallback policy.
+func (cache *Cache) GetEntry(ctx context.Context, url string) (*CacheEntry, error) {
+	origin, err := normalizeUrl(url)
+	if err != nil {
+		return &CacheEntry{}, err
+	}
+
+	cache.mu.Lock()
+	if failure, exists := cache.failures[origin]; exists && timeNow().Before(failure.retryAt) {
+		cache.countFailure(failure.entry)
+		cache.mu.Unlock()
+		return failure.entry, failure.err
+	}
+	var cacheEntry *CacheEntry
+	element, exists := cache.entries[origin]
+	if exists {
+		cacheEntry = element.Value.(*CacheEntry)
+		cache.lru.MoveToFront(element)
+	}
+	cache.mu.Unlock()
+	if !exists {
+		cacheEntry, exists = cache.load(origin)
+	}
+
+	cache.mu.Lock()
+	if exists && cacheEntry.Fresh(timeNow()) {
+		cache.stats.Hits++
+		cache.mu.Unlock()
+		return cacheEntry, nil
+	}
+	if !exists {
+		cacheEntry = nil
+		cache.stats.Misses++
+	} else if cacheEntry.revalidatable() {
+		cache.stats.Revalidations++
+	} else {
+		cache.stats.Misses++
+	}
+	cache.mu.Unlock()
+
+	value, err := cache.inFlight.do(ctx, origin, func(ctx context.Context) (interface{}, error) {
+		fetched, err := cache.fetch(ctx, origin, cacheEntry)
+		if err != nil && ctx.Err() == nil {
+			fetched, err = cache.fallback(origin, cacheEntry, err)
+		}
+		if err != nil && ctx.Err() != nil {
+			return fetched, err
+		}
+		cache.remember(origin, fetched, err)
+		if err == nil && !fetched.Stale && !fetched.Fallback {
+			cache.save(fetched)
+		}
+		return fetched, err
+	})
+	fetched, ok := value.(*CacheEntry)
+	if !ok {
+		return &CacheEntry{}, err
+	}
+	return fetched, err
+}
+
+// Stats returns a snapshot of the hit, miss, and revalidation counts.
+func (cache *Cache) Stats() CacheStats {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	return cache.stats
+}
+
+// load reads the entry of an origin from the Store and parses it, an entry that can not be read or parsed is treated as missing.
+func (cache *Cache) load(origin string) (*CacheEntry, bool) {
+	cacheEntry, exists, err := cache.store().Get(origin)
+	if err == nil && exists && cacheEntry.RobotsTxt == nil {
+		cacheEntry, err = cacheEntry.reparse()
+	}
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if err != nil {
+		cache.stats.StoreErrors++
+		return nil, false
+	}
+	if exists {
+		cache.keep(cacheEntry)
+	}
+	return cacheEntry, exists
+}
+
+// save keeps an entry in memory and writes it to the Store, an entry the Store can not write is still used from memory.
+func (cache *Cache) save(cacheEntry *CacheEntry) {
+	err := cache.store().Put(cacheEntry)
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if err != nil {
+		cache.stats.StoreErrors++
+	}
+	cache.keep(cacheEntry)
+}
+
+// keep adds a parsed entry to memory as the most recently used and evicts the least recently used ones over MaxEntries, they stay in
+// the Store. The caller must hold the lock.
+func (cache *Cache) keep(cacheEntry *CacheEntry) {
+	if cache.entries == nil {
+		cache.entries = make(map[string]*list.Element)
+		cache.lru = list.New()
+	}
+	if element, exists := cache.entries[cacheEntry.Origin]; exists {
+		cache.lru.Remove(element)
+	}
+	cache.entries[cacheEntry.Origin] = cache.lru.PushFront(cacheEntry)
+
+	for cache.lru.Len() > cache.maxEntries() {
+		evicted := cache.lru.Remove(cache.lru.Back()).(*CacheEntry)
+		delete(cache.entries, evicted.Origin)
+	}
+}
+
+func (cache *Cache) maxEntries() int {
+	if cache.MaxEntries > 0 {
+		return cache.MaxEntries
+	}
+	return defaultCacheMaxEntries
+}
+
+// remember keeps the result of a fetch when it failed so it is reused for a while, a successful fetch forgets earlier failures.
+func (cache *Cache) remember(origin string, cacheEntry *CacheEntry, err error) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if err == nil && !cacheEntry.Stale && !cacheEntry.Fallback {
+		delete(cache.failures, origin)
+		return
+	}
+
+	if cache.failures == nil {
+		cache.failures = make(map[string]*cacheFailure)
+	}
+	failures := 1
+	if failure, exists := cache.failures[origin]; exists {
+		failures = failure.failures + 1
+	}
+	retryInterval := cache.ErrorRetryInterval
+	if retryInterval <= 0 {
+		retryInterval = defaultErrorRetryInterval
+	}
+	for i := 1; i < failures && retryInterval < maxErrorRetryInterval; i++ {
+		retryInterval *= 2
+	}
+	if retryInterval > maxErrorRetryInterval {
+		retryInterval = maxErrorRetryInterval
+	}
+	cache.failures[origin] = &cacheFailure{entry: cacheEntry, err: err, retryAt: timeNow().Add(retryInterval), failures: failures}
+	cache.pruneFailures(origin)
+}
+
+// pruneFailures drops the failures that are no longer reused and too old to make the next retry interval longer, and the ones
+// closest to a retry while there are more than MaxEntries. The failure of the origin that was just added is kept, the caller must
+// hold the lock.
+func (cache *Cache) pruneFailures(origin string) {
+	now := timeNow()
+	for failureOrigin, failure := range cache.failures {
+		if now.After(failure.retryAt.Add(maxErrorRetryInterval)) {
+			delete(cache.failures, failureOrigin)
+		}
+	}
+
+	for len(cache.failures) > cache.maxEntries() {
+		var first string
+		for failureOrigin, failure := range cache.failures {
+			if failureOrigin != origin && (first == "" || failure.retryAt.Before(cache.failures[first].retryAt)) {
+				first = failureOrigin
+			}
+		}
+		delete(cache.failures, first)
+	}
+}
+
+// countFailure counts a lookup that is answered by the result of a failed fetch, the caller must hold the lock.
+func (cache *Cache) countFailure(cacheEntry *CacheEntry) {
+	switch {
+	case cacheEntry.Stale:
+		cache.stats.Stale++
+	case cacheEntry.Fallback:
+		cache.stats.Fallbacks++
+	}
+}
+
+// fetch retrieves the robots.txt for an origin, the expired entry is revalidated instead when it is possible to.
+func (cache *Cache) fetch(ctx context.Context, origin string, expired *CacheEntry) (*CacheEntry, error) {
+	fetcher := cache.Fetcher
+	if fetcher == nil {
+		fetcher = &Fetcher{}
+	}
+
+	var fetchResult *FetchResult
+	var err error
+	if expired != nil && expired.revalidatable() {
+		fetchResult, err = fetcher.Revalidate(ctx, origin, expired.ETag, expired.LastModified)
+	} else {
+		fetchResult, err = fetcher.Fetch(ctx, origin)
+	}
+	if err != nil {
+		return &CacheEntry{}, err
+	}
+
+	now := timeNow()
+	if fetchResult.StatusCode == http.StatusNotModified {
+		cache.mu.Lock()
+		cache.stats.NotModified++
+		cache.mu.Unlock()
+
+		// Entries are shared with callers so the expired entry is copied instead of being modified.
+		revalidated := *expired
+		revalidated.Expires = now.Add(cache.ttl(fetchResult.Header, now))
+		if etag := fetchResult.Header.Get("ETag"); etag != "" {
+			revalidated.ETag = etag
+		}
+		return &revalidated, nil
+	}
+
+	return &CacheEntry{
+		Origin:       origin,
+		RobotsTxt:    fetchResult.RobotsTxt,
+		URL:          fetchResult.URL,
+		FinalURL:     fetchResult.FinalURL,
+		Redirects:    fetchResult.Redirects,
+		StatusCode:   fetchResult.StatusCode,
+		Body:         fetchResult.Body,
+		FetchedAt:    now,
+		Expires:      now.Add(cache.ttl(fetchResult.Header, now)),
+		ETag:         fetchResult.Header.Get("ETag"),
+		LastModified: fetchResult.Header.Get("Last-Modified"),
+	}, nil
+}
+
+// fallback serves the expired entry while it is within the stale window, otherwise the FallbackPolicy is applied. Both only happen
+// when the robots.txt is unreachable.
+func (cache *Cache) fallback(origin string, expired *CacheEntry, fetchErr error) (*CacheEntry, error) {
+	if !unreachable(fetchErr) {
+		return &CacheEntry{}, fetchErr
+	}
+
+	staleIfError := cache.StaleIfError
+	if staleIfError > maxStaleIfError {
+		staleIfError = maxStaleIfError
+	}
+
+	now := timeNow()
+	if expired != nil && now.Before(expired.Expires.Add(staleIfError)) {
+		cache.mu.Lock()
+		cache.stats.Stale++
+		cache.mu.Unlock()
+
+		stale := *expired
+		stale.Stale = true
+		return &stale, nil
+	}
+
+	var robotsTxt *RobotsTxt
+	var err error
+	switch cache.FallbackPolicy {
+	case FallbackDisallowAll:
+		robotsTxt, err = parse(origin, strings.NewReader("User-agent: *\nDisallow: /\n"))
+	case FallbackAllowAll:
+		robotsTxt, err = parse(origin, strings.NewReader(""))
+	default:
+		return &CacheEntry{}, fetchErr
+	}
+	if err != nil {
+		return &CacheEntry{}, err
+	}
+
+	cache.mu.Lock()
+	cache.stats.Fallbacks++
+	cache.mu.Unlock()
+	return &CacheEntry{
+		Origin:    origin,
+		RobotsTxt: robotsTxt,
+		FetchedAt: now,
+		Expires:   now,
+		Fallback:  true,
+	}, nil
+}
+
+// unreachable reports whether a fetch failed because the robots.txt is unreachable, the server responded with a 5xx or did not
+// respond at all, https://www.rfc-editor.org/rfc/rfc9309.html#section-2.3.1.4.
+func unreachable(err error) bool {
+	var fetchError *FetchError
+	if !errors.As(err, &fetchError) {
+		return false
+	}
+	var redirectError *redirectError
+	return fetchError.Status >= 500 || (fetchError.Status == 0 && !errors.As(err, &redirectError))
+}
+
+func (cache *Cache) store() Store {
+	if cache.Store != nil {
+		return cache.Store
+	}
+	return &cache.memoryStore
+}
+
+// reparse returns a copy of the entry with the RobotsTxt parsed from the raw body.
+func (cacheEntry *CacheEntry) reparse() (*CacheEntry, error) {
+	robotsTxt, err := parseResponse(cacheEntry.Origin, cacheEntry.URL, cacheEntry.StatusCode, cacheEntry.Body)
+	if err != nil {
+		return &CacheEntry{}, err
+	}
+
+	reparsed := *cacheEntry
+	reparsed.RobotsTxt = robotsTxt
+	return &reparsed, nil
+}
+
+func (cacheEntry *CacheEntry) revalidatable() bool {
+	return cacheEntry.ETag != "" || cacheEntry.LastModified != ""
+}
+
+// ttl determines how long a response can be cached, "Cache-Control: max-age" takes precedence over "Expires",
+// https://tools.ietf.org/html/rfc7234#section-4.2.1.
+func (cache *Cache) ttl(header http.Header, now time.Time) time.Duration {
+	ttl := cache.DefaultTTL
+	if ttl <= 0 {
+		ttl = defaultCacheTTL
+	}
+
+	if maxAge, exists := cacheControlMaxAge(header.Get("Cache-Control")); exists {
+		ttl = maxAge
+	} else if expires := header.Get("Expires"); expires != "" {
+		// An invalid date, such as "0", means the response is already expired.
+		ttl = 0
+		if expiresAt, err := http.ParseTime(expires); err == nil {
+			date := now
+			if dateAt, err := http.ParseTime(header.Get("Date")); err == nil {
+				date = dateAt
+			}
+			ttl = expiresAt.Sub(date)
+		}
+	}
+
+	maxTTL := cache.MaxTTL
+	if maxTTL <= 0 {
+		maxTTL = maxCacheTTL
+	}
+	if ttl > maxTTL {
+		ttl = maxTTL
+	}
+	if ttl < 0 {
+		ttl = 0
+	}
+	return ttl
+}
+
+func cacheControlMaxAge(cacheControl string) (time.Duration, bool) {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+		if directive == "no-cache" || directive == "no-store" {
+			return 0, true
+		}
+		if !strings.HasPrefix(directive, "max-age=") {
+			continue
+		}
+
+		seconds, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(directive, "max-age="), `"`))
+		if err != nil {
+			continue
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	return 0, false
+}
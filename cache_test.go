@@ -0,0 +1,383 @@
+package robotstxt
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCache_Get(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		_, _ = w.Write([]byte("User-agent: *\nDisallow: /cms/\n"))
+	}))
+	defer server.Close()
+
+	cache := &Cache{}
+	for _, url := range []string{server.URL, server.URL + "/pricing/roll-off-dumpsters", server.URL + "/robots.txt"} {
+		robotsTxt, err := cache.Get(context.Background(), url)
+		assert.Nil(t, err)
+		canCrawl, err := robotsTxt.CanCrawl("googlebot", "/cms/pages")
+		assert.Nil(t, err)
+		assert.False(t, canCrawl)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+}
+
+func TestCache_Get_refetches_expired_entries(t *testing.T) {
+	old := timeNow
+	defer func() { timeNow = old }()
+	now := time.Date(2019, 6, 15, 0, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Cache-Control", "public, max-age=60")
+		_, _ = w.Write([]byte("User-agent: *\nDisallow: /cms/\n"))
+	}))
+	defer server.Close()
+
+	cache := &Cache{}
+	cacheEntry, err := cache.GetEntry(context.Background(), server.URL)
+	assert.Nil(t, err)
+	assert.Equal(t, now.Add(60*time.Second), cacheEntry.Expires)
+
+	now = now.Add(59 * time.Second)
+	_, err = cache.Get(context.Background(), server.URL)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+
+	now = now.Add(time.Second)
+	_, err = cache.Get(context.Background(), server.URL)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+}
+
+func TestCache_Get_deduplicates_concurrent_fetches(t *testing.T) {
+	var requests int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		<-release
+		_, _ = w.Write([]byte("User-agent: *\nDisallow: /cms/\n"))
+	}))
+	defer server.Close()
+
+	cache := &Cache{}
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := cache.Get(context.Background(), server.URL)
+			assert.Nil(t, err)
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+}
+
+func TestCache_Get_does_not_share_the_cancellation_of_another_caller(t *testing.T) {
+	var requests int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		select {
+		case <-release:
+		case <-r.Context().Done():
+			return
+		}
+		_, _ = w.Write([]byte("User-agent: *\nDisallow: /cms/\n"))
+	}))
+	defer server.Close()
+
+	cache := &Cache{}
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error)
+	go func() {
+		_, err := cache.Get(ctx, server.URL)
+		first <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	second := make(chan error)
+	go func() {
+		_, err := cache.Get(context.Background(), server.URL)
+		second <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	cancel()
+	assert.NotNil(t, <-first)
+	close(release)
+	assert.Nil(t, <-second)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+}
+
+func TestCache_ttl(t *testing.T) {
+	now := time.Date(2019, 6, 15, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		header http.Header
+		ttl    time.Duration
+	}{
+		{header: http.Header{}, ttl: 24 * time.Hour},
+		{header: http.Header{"Cache-Control": {"max-age=3600"}}, ttl: time.Hour},
+		{header: http.Header{"Cache-Control": {"no-cache"}}, ttl: 0},
+		{header: http.Header{"Cache-Control": {"max-age=31536000"}}, ttl: 24 * time.Hour},
+		{header: http.Header{"Expires": {now.Add(2 * time.Hour).Format(http.TimeFormat)}}, ttl: 2 * time.Hour},
+		{header: http.Header{"Expires": {"0"}}, ttl: 0},
+		{
+			header: http.Header{"Cache-Control": {"max-age=60"}, "Expires": {now.Add(2 * time.Hour).Format(http.TimeFormat)}},
+			ttl:    time.Minute,
+		},
+		{
+			header: http.Header{"Date": {now.Add(-time.Hour).Format(http.TimeFormat)}, "Expires": {now.Format(http.TimeFormat)}},
+			ttl:    time.Hour,
+		},
+	}
+
+	cache := &Cache{}
+	for _, test := range tests {
+		assert.Equal(t, test.ttl, cache.ttl(test.header, now), "%v", test.header)
+	}
+	assert.Equal(t, time.Hour, (&Cache{DefaultTTL: time.Hour}).ttl(http.Header{}, now))
+	assert.Equal(t, time.Minute, (&Cache{MaxTTL: time.Minute}).ttl(http.Header{"Cache-Control": {"max-age=3600"}}, now))
+}
+
+func TestCache_Get_revalidates_expired_entries(t *testing.T) {
+	old := timeNow
+	defer func() { timeNow = old }()
+	now := time.Date(2019, 6, 15, 0, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+
+	var requests, notModified int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", "Sat, 15 Jun 2019 00:00:00 GMT")
+		if r.Header.Get("If-None-Match") == `"v1"` && r.Header.Get("If-Modified-Since") == "Sat, 15 Jun 2019 00:00:00 GMT" {
+			atomic.AddInt32(&notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		_, _ = w.Write([]byte("User-agent: *\nDisallow: /cms/\n"))
+	}))
+	defer server.Close()
+
+	cache := &Cache{}
+	first, err := cache.GetEntry(context.Background(), server.URL)
+	assert.Nil(t, err)
+	assert.Equal(t, `"v1"`, first.ETag)
+
+	_, err = cache.Get(context.Background(), server.URL)
+	assert.Nil(t, err)
+
+	now = now.Add(time.Minute)
+	second, err := cache.GetEntry(context.Background(), server.URL)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&notModified))
+	assert.True(t, first.RobotsTxt == second.RobotsTxt, "the robots.txt should not be parsed again")
+	assert.Equal(t, now.Add(time.Minute), second.Expires)
+	assert.Equal(t, now.Add(-time.Minute), second.FetchedAt)
+
+	canCrawl, err := second.RobotsTxt.CanCrawl("googlebot", "/cms/pages")
+	assert.Nil(t, err)
+	assert.False(t, canCrawl)
+
+	assert.Equal(t, CacheStats{Hits: 1, Misses: 1, Revalidations: 1, NotModified: 1}, cache.Stats())
+}
+
+func TestCache_Get_serves_stale_entries_on_error(t *testing.T) {
+	old := timeNow
+	defer func() { timeNow = old }()
+	now := time.Date(2019, 6, 15, 0, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+
+	statusCode := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=3600")
+		w.WriteHeader(statusCode)
+		_, _ = w.Write([]byte("User-agent: *\nDisallow: /cms/\n"))
+	}))
+	defer server.Close()
+
+	cache := &Cache{StaleIfError: 48 * time.Hour, FallbackPolicy: FallbackDisallowAll}
+	_, err := cache.Get(context.Background(), server.URL)
+	assert.Nil(t, err)
+
+	statusCode = http.StatusServiceUnavailable
+	now = now.Add(47 * time.Hour)
+	cacheEntry, err := cache.GetEntry(context.Background(), server.URL)
+	assert.Nil(t, err)
+	assert.True(t, cacheEntry.Stale)
+	assert.False(t, cacheEntry.Fallback)
+	canCrawl, err := cacheEntry.RobotsTxt.CanCrawl("googlebot", "/products/")
+	assert.Nil(t, err)
+	assert.True(t, canCrawl)
+
+	now = now.Add(2 * time.Hour)
+	cacheEntry, err = cache.GetEntry(context.Background(), server.URL)
+	assert.Nil(t, err)
+	assert.False(t, cacheEntry.Stale)
+	assert.True(t, cacheEntry.Fallback)
+	canCrawl, err = cacheEntry.RobotsTxt.CanCrawl("googlebot", "/products/")
+	assert.Nil(t, err)
+	assert.False(t, canCrawl)
+
+	// The site recovered, the fallback is used until the retry interval that doubled after the second failure passed.
+	statusCode = http.StatusOK
+	cacheEntry, err = cache.GetEntry(context.Background(), server.URL)
+	assert.Nil(t, err)
+	assert.True(t, cacheEntry.Fallback)
+	now = now.Add(2 * time.Minute)
+	cacheEntry, err = cache.GetEntry(context.Background(), server.URL)
+	assert.Nil(t, err)
+	assert.False(t, cacheEntry.Stale)
+	assert.False(t, cacheEntry.Fallback)
+
+	stats := cache.Stats()
+	assert.Equal(t, uint64(1), stats.Stale)
+	assert.Equal(t, uint64(2), stats.Fallbacks)
+}
+
+func TestCache_Get_backs_off_while_a_robots_txt_is_unreachable(t *testing.T) {
+	old := timeNow
+	defer func() { timeNow = old }()
+	now := time.Date(2019, 6, 15, 0, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	cache := &Cache{ErrorRetryInterval: time.Minute}
+	for _, wait := range []time.Duration{0, time.Minute, 2 * time.Minute, 4 * time.Minute} {
+		now = now.Add(wait)
+		for i := 0; i < 3; i++ {
+			_, err := cache.Get(context.Background(), server.URL)
+			assert.NotNil(t, err)
+		}
+	}
+	assert.Equal(t, int32(4), atomic.LoadInt32(&requests))
+
+	now = now.Add(7 * time.Minute)
+	_, _ = cache.Get(context.Background(), server.URL)
+	assert.Equal(t, int32(4), atomic.LoadInt32(&requests))
+	now = now.Add(time.Minute)
+	_, _ = cache.Get(context.Background(), server.URL)
+	assert.Equal(t, int32(5), atomic.LoadInt32(&requests))
+}
+
+func TestCache_Get_keeps_MaxEntries_in_memory(t *testing.T) {
+	var requests int32
+	var urls []string
+	for i := 0; i < 3; i++ {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			_, _ = w.Write([]byte("User-agent: *\nDisallow: /cms/\n"))
+		}))
+		defer server.Close()
+		urls = append(urls, server.URL)
+	}
+
+	store := &MemoryStore{}
+	cache := &Cache{Store: store, MaxEntries: 2}
+	for _, url := range urls {
+		_, err := cache.Get(context.Background(), url)
+		assert.Nil(t, err)
+	}
+	assert.Len(t, cache.entries, 2)
+	assert.Equal(t, 2, cache.lru.Len())
+
+	// The evicted entry is read from the Store instead of being fetched again.
+	_, err := cache.Get(context.Background(), urls[0])
+	assert.Nil(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+	assert.Len(t, cache.entries, 2)
+	assert.Equal(t, CacheStats{Hits: 1, Misses: 3}, cache.Stats())
+}
+
+func TestCache_Get_forgets_old_failures(t *testing.T) {
+	old := timeNow
+	defer func() { timeNow = old }()
+	now := time.Date(2019, 6, 15, 0, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+
+	var urls []string
+	for i := 0; i < 4; i++ {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+		urls = append(urls, server.URL)
+	}
+
+	cache := &Cache{MaxEntries: 2}
+	for _, url := range urls[:3] {
+		_, err := cache.Get(context.Background(), url)
+		assert.NotNil(t, err)
+		now = now.Add(time.Second)
+	}
+	assert.Len(t, cache.failures, 2)
+
+	// Failures that can no longer make a retry interval longer are dropped when another one is kept.
+	now = now.Add(2 * time.Hour)
+	_, err := cache.Get(context.Background(), urls[3])
+	assert.NotNil(t, err)
+	assert.Len(t, cache.failures, 1)
+}
+
+func TestCache_Get_only_falls_back_when_a_robots_txt_is_unreachable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			http.Redirect(w, r, "https://www.example.com/robots.txt", http.StatusMovedPermanently)
+		}
+	}))
+	defer server.Close()
+
+	cache := &Cache{Fetcher: &Fetcher{RejectCrossSiteRedirects: true}, FallbackPolicy: FallbackAllowAll}
+	_, err := cache.Get(context.Background(), server.URL)
+	assert.NotNil(t, err)
+
+	unreachableServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	unreachableServer.Close()
+	cacheEntry, err := cache.GetEntry(context.Background(), unreachableServer.URL)
+	assert.Nil(t, err)
+	assert.True(t, cacheEntry.Fallback)
+}
+
+func TestCache_Get_fallback_policies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	_, err := (&Cache{}).Get(context.Background(), server.URL)
+	assert.NotNil(t, err)
+
+	robotsTxt, err := (&Cache{FallbackPolicy: FallbackAllowAll}).Get(context.Background(), server.URL)
+	assert.Nil(t, err)
+	canCrawl, err := robotsTxt.CanCrawl("googlebot", "/products/")
+	assert.Nil(t, err)
+	assert.True(t, canCrawl)
+
+	robotsTxt, err = (&Cache{FallbackPolicy: FallbackDisallowAll}).Get(context.Background(), server.URL)
+	assert.Nil(t, err)
+	canCrawl, err = robotsTxt.CanCrawl("googlebot", "/products/")
+	assert.Nil(t, err)
+	assert.False(t, canCrawl)
+}
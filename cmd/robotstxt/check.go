@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"github.com/itmayziii/robotstxt/v2"
+	"io"
+)
+
+const checkUsage = "[-f source] [-url url] [-json] <agent> <url ...>"
+
+type checkResult struct {
+	URL     string `json:"url"`
+	Allowed bool   `json:"allowed"`
+	Error   string `json:"error,omitempty"`
+}
+
+type explainResult struct {
+	URL     string    `json:"url"`
+	Allowed bool      `json:"allowed"`
+	Agent   string    `json:"agent,omitempty"`
+	Rule    *jsonRule `json:"rule,omitempty"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// checkOptions are the arguments check, explain, and subtree have in common.
+type checkOptions struct {
+	agent     string
+	urls      []string
+	robotsTxt *robotstxt.RobotsTxt
+	json      bool
+}
+
+// runCheck reports whether an agent can crawl URLs, the exit code is 1 when one of them can not be crawled.
+func runCheck(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	options, exitCode := parseCheckArgs("check", checkUsage, args, stdin, stderr)
+	if options == nil {
+		return exitCode
+	}
+
+	var results []checkResult
+	for _, url := range options.urls {
+		canCrawl, err := options.robotsTxt.CanCrawl(options.agent, url)
+		result := checkResult{URL: url, Allowed: canCrawl}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+
+	if options.json {
+		_ = writeJSON(stdout, results)
+	}
+	exitCode = 0
+	for _, result := range results {
+		switch {
+		case result.Error != "":
+			fmt.Fprintf(stderr, "robotstxt check: %s: %s\n", result.URL, result.Error)
+			exitCode = 2
+		case !options.json && result.Allowed:
+			fmt.Fprintf(stdout, "allowed\t%s\n", result.URL)
+		case !options.json:
+			fmt.Fprintf(stdout, "disallowed\t%s\n", result.URL)
+		}
+		if !result.Allowed && exitCode == 0 {
+			exitCode = 1
+		}
+	}
+	return exitCode
+}
+
+// runExplain is runCheck that also shows the group and rule that decided.
+func runExplain(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	options, exitCode := parseCheckArgs("explain", checkUsage, args, stdin, stderr)
+	if options == nil {
+		return exitCode
+	}
+
+	var results []explainResult
+	for _, url := range options.urls {
+		explanation, err := options.robotsTxt.Explain(options.agent, url)
+		result := explainResult{URL: url, Allowed: explanation.CanCrawl, Agent: explanation.Agent}
+		if explanation.Rule != nil {
+			result.Rule = newJSONRule(explanation.Rule)
+		}
+		if err != nil {
+			result.Allowed = false
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+
+	if options.json {
+		_ = writeJSON(stdout, results)
+	}
+	exitCode = 0
+	for _, result := range results {
+		if result.Error != "" {
+			fmt.Fprintf(stderr, "robotstxt explain: %s: %s\n", result.URL, result.Error)
+			exitCode = 2
+			continue
+		}
+		if !result.Allowed && exitCode == 0 {
+			exitCode = 1
+		}
+		if options.json {
+			continue
+		}
+
+		verdict := "allowed"
+		if !result.Allowed {
+			verdict = "disallowed"
+		}
+		switch {
+		case result.Agent == "":
+			fmt.Fprintf(stdout, "%s: %s, no group applies to %s\n", result.URL, verdict, options.agent)
+		case result.Rule == nil:
+			fmt.Fprintf(stdout, "%s: %s, no rule of the group of %q matches\n", result.URL, verdict, result.Agent)
+		default:
+			fmt.Fprintf(stdout, "%s: %s by %q in the group of %q\n", result.URL, verdict, robotstxt.Rule{Allow: result.Rule.Allow, Path: result.Rule.Path}.String(), result.Agent)
+		}
+	}
+	return exitCode
+}
+
+// parseCheckArgs reads the arguments and the robots.txt of check, explain, and subtree, the options are nil when the command has to
+// exit.
+func parseCheckArgs(name, usage string, args []string, stdin io.Reader, stderr io.Writer) (*checkOptions, int) {
+	flags := newFlagSet(name, usage, stderr)
+	source := flags.String("f", "", "read the robots.txt from a file, an http(s) URL, or standard input with \"-\" instead of fetching it for the first URL")
+	url := flags.String("url", "", "URL the robots.txt applies to when it is read from a file or standard input, the first absolute URL is used by default")
+	options := &checkOptions{}
+	flags.BoolVar(&options.json, "json", false, "write the results as JSON")
+	if err := flags.Parse(args); err != nil {
+		return nil, 2
+	}
+	if flags.NArg() < 2 {
+		flags.Usage()
+		return nil, 2
+	}
+	options.agent = flags.Arg(0)
+	options.urls = flags.Args()[1:]
+
+	options.robotsTxt = loadRobotsTxtForURLs(name, *source, *url, options.urls, stdin, stderr)
+	if options.robotsTxt == nil {
+		return nil, 2
+	}
+	return options, 0
+}
+
+// loadRobotsTxtForURLs reads the robots.txt of a source, or fetches the one of the first absolute URL when there is no source. It
+// reports why on stderr and returns nil when there is no robots.txt.
+func loadRobotsTxtForURLs(name, source, url string, urls []string, stdin io.Reader, stderr io.Writer) *robotstxt.RobotsTxt {
+	if url == "" {
+		url = firstAbsoluteURL(urls)
+	}
+	if source == "" {
+		if url == "" {
+			fmt.Fprintf(stderr, "robotstxt %s: a URL with a scheme and host or -f is needed to know which robots.txt to use\n", name)
+			return nil
+		}
+		source = url
+	}
+
+	robotsTxt, err := loadRobotsTxt(source, url, stdin)
+	if err != nil {
+		fmt.Fprintf(stderr, "robotstxt %s: %v\n", name, err)
+		return nil
+	}
+	return robotsTxt
+}
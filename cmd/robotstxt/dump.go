@@ -0,0 +1,113 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/itmayziii/robotstxt/v2"
+	"io"
+	"strconv"
+)
+
+type jsonRule struct {
+	Allow bool   `json:"allow"`
+	Path  string `json:"path"`
+}
+
+type jsonGroup struct {
+	Agents     []string   `json:"agents"`
+	CrawlDelay float64    `json:"crawlDelay"` // Seconds.
+	Rules      []jsonRule `json:"rules"`
+}
+
+type jsonRobotsTxt struct {
+	Groups   []jsonGroup `json:"groups"`
+	Sitemaps []string    `json:"sitemaps"`
+}
+
+type delayResult struct {
+	Agent      string  `json:"agent"`
+	CrawlDelay float64 `json:"crawlDelay"` // Seconds.
+}
+
+// runSitemaps lists the sitemaps of a robots.txt.
+func runSitemaps(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := newFlagSet("sitemaps", "[-json] [source]", stderr)
+	asJSON := flags.Bool("json", false, "write the sitemaps as a JSON array")
+	robotsTxt, exitCode := parseSourceArgs("sitemaps", flags, args, 0, stdin, stderr)
+	if robotsTxt == nil {
+		return exitCode
+	}
+
+	sitemaps := robotsTxt.Sitemaps()
+	if *asJSON {
+		if sitemaps == nil {
+			sitemaps = []string{}
+		}
+		_ = writeJSON(stdout, sitemaps)
+		return 0
+	}
+	for _, sitemap := range sitemaps {
+		fmt.Fprintln(stdout, sitemap)
+	}
+	return 0
+}
+
+// runDelay shows the crawl delay of an agent in seconds.
+func runDelay(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := newFlagSet("delay", "[-json] <agent> [source]", stderr)
+	asJSON := flags.Bool("json", false, "write the crawl delay as JSON")
+	robotsTxt, exitCode := parseSourceArgs("delay", flags, args, 1, stdin, stderr)
+	if robotsTxt == nil {
+		return exitCode
+	}
+
+	result := delayResult{Agent: flags.Arg(0), CrawlDelay: robotsTxt.CrawlDelay(flags.Arg(0)).Seconds()}
+	if *asJSON {
+		_ = writeJSON(stdout, result)
+		return 0
+	}
+	fmt.Fprintln(stdout, strconv.FormatFloat(result.CrawlDelay, 'f', -1, 64))
+	return 0
+}
+
+// runDump writes the groups, rules, and sitemaps of a robots.txt as JSON.
+func runDump(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := newFlagSet("dump", "[source]", stderr)
+	robotsTxt, exitCode := parseSourceArgs("dump", flags, args, 0, stdin, stderr)
+	if robotsTxt == nil {
+		return exitCode
+	}
+
+	dump := jsonRobotsTxt{Groups: []jsonGroup{}, Sitemaps: robotsTxt.Sitemaps()}
+	if dump.Sitemaps == nil {
+		dump.Sitemaps = []string{}
+	}
+	for _, group := range robotsTxt.Groups() {
+		jsonGroup := jsonGroup{Agents: group.Agents, CrawlDelay: group.CrawlDelay.Seconds(), Rules: []jsonRule{}}
+		for _, rule := range group.Rules {
+			jsonGroup.Rules = append(jsonGroup.Rules, jsonRule{Allow: rule.Allow, Path: rule.Path})
+		}
+		dump.Groups = append(dump.Groups, jsonGroup)
+	}
+	_ = writeJSON(stdout, dump)
+	return 0
+}
+
+// parseSourceArgs parses the flags of a command that takes a number of arguments followed by an optional source and reads the
+// robots.txt, the robots.txt is nil when the command has to exit.
+func parseSourceArgs(name string, flags *flag.FlagSet, args []string, arguments int, stdin io.Reader, stderr io.Writer) (*robotstxt.RobotsTxt, int) {
+	if err := flags.Parse(args); err != nil {
+		return nil, 2
+	}
+	if flags.NArg() < arguments || flags.NArg() > arguments+1 {
+		flags.Usage()
+		return nil, 2
+	}
+
+	robotsTxt, err := loadRobotsTxt(flags.Arg(arguments), "", stdin)
+	if err != nil {
+		fmt.Fprintf(stderr, "robotstxt %s: %v\n", name, err)
+		return nil, 2
+	}
+	return robotsTxt, 0
+}
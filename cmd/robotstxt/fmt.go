@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/itmayziii/robotstxt/v2"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+type fmtOptions struct {
+	list  bool
+	write bool
+	diff  bool
+}
+
+// runFmt formats robots.txt files the way gofmt formats Go files, directories are searched for files named robots.txt.
+func runFmt(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := newFlagSet("fmt", "[-l] [-w] [-d] [path ...]", stderr)
+	options := fmtOptions{}
+	flags.BoolVar(&options.list, "l", false, "list files whose formatting differs from robotstxt fmt's")
+	flags.BoolVar(&options.write, "w", false, "write result to (source) file instead of stdout")
+	flags.BoolVar(&options.diff, "d", false, "display diffs instead of rewriting files")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if flags.NArg() == 0 {
+		if options.write {
+			fmt.Fprintln(stderr, "robotstxt fmt: can not use -w with standard input")
+			return 2
+		}
+		if err := formatFile("<standard input>", stdin, stdout, options); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 2
+		}
+		return 0
+	}
+
+	exitCode := 0
+	for _, path := range flags.Args() {
+		err := filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			// Files that are named explicitly are always formatted.
+			if info.IsDir() || (info.Name() != "robots.txt" && !isArg(flags, path)) {
+				return nil
+			}
+			file, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			return formatFile(path, file, stdout, options)
+		})
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			exitCode = 2
+		}
+	}
+	return exitCode
+}
+
+func formatFile(path string, reader io.Reader, stdout io.Writer, options fmtOptions) error {
+	source, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	var formatted bytes.Buffer
+	err = robotstxt.Format(bytes.NewReader(source), &formatted)
+	var encodingError *robotstxt.EncodingError
+	if errors.As(err, &encodingError) {
+		return fmt.Errorf("%s:%d: %w", path, encodingError.Line, err)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	if bytes.Equal(source, formatted.Bytes()) {
+		if !options.list && !options.write && !options.diff {
+			_, err = stdout.Write(formatted.Bytes())
+		}
+		return err
+	}
+
+	if options.list {
+		fmt.Fprintln(stdout, path)
+	}
+	if options.write {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		err = writeFile(path, formatted.Bytes(), info.Mode().Perm())
+		if err != nil {
+			return err
+		}
+	}
+	if options.diff {
+		fmt.Fprintf(stdout, "diff %s.orig %s\n", path, path)
+		writeDiff(stdout, path+".orig", path, string(source), formatted.String())
+	}
+	if !options.list && !options.write && !options.diff {
+		_, err = stdout.Write(formatted.Bytes())
+	}
+	return err
+}
+
+// writeFile writes to a temporary file in the same directory first and renames it, so the file is never left half written.
+func writeFile(path string, data []byte, perm os.FileMode) error {
+	file, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".tmp-")
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(data)
+	if err == nil {
+		err = file.Chmod(perm)
+	}
+	if err == nil {
+		err = file.Sync()
+	}
+	closeErr := file.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(file.Name())
+		return err
+	}
+
+	err = os.Rename(file.Name(), path)
+	if err != nil {
+		_ = os.Remove(file.Name())
+	}
+	return err
+}
+
+func isArg(flags *flag.FlagSet, path string) bool {
+	for _, arg := range flags.Args() {
+		if arg == path {
+			return true
+		}
+	}
+	return false
+}
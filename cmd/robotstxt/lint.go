@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/itmayziii/robotstxt/v2"
+	"io"
+	"os"
+)
+
+// sarifSchema is the JSON schema of the SARIF 2.1.0 output, https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html.
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+type lintResult struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Code     string `json:"code"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	Fix      string `json:"fix"`
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// runLint checks robots.txt files for mistakes, the exit code is 1 when a warning or error is found.
+func runLint(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := newFlagSet("lint", "[-format text|json|sarif] [source ...]", stderr)
+	format := flags.String("format", "text", "write the findings as text, json, or sarif")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *format != "text" && *format != "json" && *format != "sarif" {
+		fmt.Fprintf(stderr, "robotstxt lint: unknown format %q\n", *format)
+		flags.Usage()
+		return 2
+	}
+
+	sources := flags.Args()
+	if len(sources) == 0 {
+		sources = []string{"-"}
+	}
+	exitCode := 0
+	results := []lintResult{}
+	for _, source := range sources {
+		name := source
+		if source == "-" {
+			name = "<standard input>"
+		}
+		findings, err := lintSource(source, stdin)
+		if err != nil {
+			fmt.Fprintf(stderr, "robotstxt lint: %s: %v\n", name, err)
+			exitCode = 2
+			continue
+		}
+		for _, finding := range findings {
+			if finding.Severity >= robotstxt.SeverityWarning && exitCode == 0 {
+				exitCode = 1
+			}
+			results = append(results, lintResult{File: name, Line: finding.Line, Code: finding.Code, Severity: finding.Severity.String(),
+				Message: finding.Message, Fix: finding.Fix})
+		}
+	}
+
+	switch *format {
+	case "json":
+		_ = writeJSON(stdout, results)
+	case "sarif":
+		_ = writeJSON(stdout, newSarifLog(results))
+	default:
+		for _, result := range results {
+			fmt.Fprintf(stdout, "%s:%d: %s %s: %s\n", result.File, result.Line, result.Severity, result.Code, result.Message)
+			fmt.Fprintf(stdout, "\tfix: %s\n", result.Fix)
+		}
+	}
+	return exitCode
+}
+
+// lintSource lints the robots.txt of a source the way loadRobotsTxt reads it, a fetched robots.txt has to exist.
+func lintSource(source string, stdin io.Reader) ([]robotstxt.Finding, error) {
+	switch {
+	case source == "" || source == "-":
+		return robotstxt.Lint(stdin)
+	case isHTTP(source):
+		ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+		defer cancel()
+		fetcher := &robotstxt.Fetcher{UserAgent: "robotstxt"}
+		// A robots.txt that can not be parsed is still linted, the findings tell why.
+		fetchResult, err := fetcher.Fetch(ctx, source)
+		if fetchResult.StatusCode == 0 {
+			return nil, err
+		}
+		if fetchResult.StatusCode < 200 || fetchResult.StatusCode > 299 {
+			return nil, fmt.Errorf("no robots.txt at %s, the status code is %d", fetchResult.URL, fetchResult.StatusCode)
+		}
+		file, err := robotstxt.ParseFile(bytes.NewReader(fetchResult.Body))
+		if err != nil {
+			return nil, err
+		}
+		return file.Lint(), nil
+	default:
+		file, err := os.Open(source)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		return robotstxt.Lint(file)
+	}
+}
+
+func newSarifLog(results []lintResult) sarifLog {
+	driver := sarifDriver{Name: "robotstxt", InformationURI: "https://github.com/itmayziii/robotstxt", Rules: []sarifRule{}}
+	for _, lintRule := range robotstxt.LintRules {
+		driver.Rules = append(driver.Rules, sarifRule{ID: lintRule.Code, ShortDescription: sarifMessage{Text: lintRule.Summary},
+			DefaultConfiguration: sarifConfiguration{Level: sarifLevel(lintRule.Severity.String())}})
+	}
+
+	run := sarifRun{Tool: sarifTool{Driver: driver}, Results: []sarifResult{}}
+	for _, result := range results {
+		location := sarifLocation{PhysicalLocation: sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: result.File},
+			Region:           sarifRegion{StartLine: result.Line},
+		}}
+		run.Results = append(run.Results, sarifResult{RuleID: result.Code, Level: sarifLevel(result.Severity),
+			Message: sarifMessage{Text: result.Message + ", " + result.Fix}, Locations: []sarifLocation{location}})
+	}
+	return sarifLog{Schema: sarifSchema, Version: "2.1.0", Runs: []sarifRun{run}}
+}
+
+// sarifLevel converts a severity to a SARIF level, which calls an info a note.
+func sarifLevel(severity string) string {
+	if severity == robotstxt.SeverityInfo.String() {
+		return "note"
+	}
+	return severity
+}
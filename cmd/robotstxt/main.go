@@ -0,0 +1,101 @@
+/*
+Robotstxt works with robots.txt files.
+
+Usage:
+
+	robotstxt <command> [arguments]
+
+The commands are:
+
+	check      report whether an agent can crawl URLs
+	explain    show the group and rule that decide whether an agent can crawl URLs
+	subtree    report whether an agent can crawl all, none, or some of the paths under prefixes
+	matrix     show which agents can crawl which URLs
+	sitemaps   list the sitemaps
+	delay      show the crawl delay of an agent in seconds
+	dump       write the groups, rules, and sitemaps as JSON
+	diff       report what changed for crawlers between two robots.txt files
+	equivalent report whether two robots.txt files allow the same paths
+	minimize   remove the rules that never make a difference
+	fmt        format robots.txt files
+	lint       check robots.txt files for mistakes
+	test       check a robots.txt against expectations files
+
+A robots.txt is read from standard input when the source is "-" or left out, fetched when the source is an http(s) URL, and read
+from a file otherwise. Check, explain, subtree, and matrix fetch the robots.txt of the first absolute URL when no source is given
+with -f:
+
+	robotstxt check googlebot https://www.dumpsters.com/cms/ https://www.dumpsters.com/products/
+	robotstxt explain -f robots.txt googlebot /cms/
+	robotstxt subtree googlebot https://www.dumpsters.com/cms/
+	robotstxt matrix -f robots.txt /cms/ /products/
+	robotstxt diff robots.txt https://www.dumpsters.com
+	robotstxt test -junit report.xml robots.txt robots_expectations.txt
+	curl -s https://www.dumpsters.com/robots.txt | robotstxt dump | jq '.groups[].agents'
+
+The exit code is 0 on success, 1 when check or explain find a URL that can not be crawled, subtree finds a prefix with a path that
+can not be crawled, diff finds that the new robots.txt is more restrictive, equivalent finds a difference, test finds an
+expectation that is not met, or lint finds a warning or error, and 2 for any error. Most commands write JSON instead of text with
+-json, lint writes JSON or SARIF with -format json or -format sarif, and test writes JUnit XML with -junit.
+
+Run "robotstxt <command> -h" to see the arguments of a command.
+*/
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// command is a subcommand of robotstxt, run returns the exit code.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string, stdin io.Reader, stdout, stderr io.Writer) int
+}
+
+var commands = []command{
+	{name: "check", summary: "report whether an agent can crawl URLs", run: runCheck},
+	{name: "explain", summary: "show the group and rule that decide whether an agent can crawl URLs", run: runExplain},
+	{name: "subtree", summary: "report whether an agent can crawl all, none, or some of the paths under prefixes", run: runSubtree},
+	{name: "matrix", summary: "show which agents can crawl which URLs", run: runMatrix},
+	{name: "sitemaps", summary: "list the sitemaps", run: runSitemaps},
+	{name: "delay", summary: "show the crawl delay of an agent in seconds", run: runDelay},
+	{name: "dump", summary: "write the groups, rules, and sitemaps as JSON", run: runDump},
+	{name: "diff", summary: "report what changed for crawlers between two robots.txt files", run: runDiff},
+	{name: "equivalent", summary: "report whether two robots.txt files allow the same paths", run: runEquivalent},
+	{name: "minimize", summary: "remove the rules that never make a difference", run: runMinimize},
+	{name: "fmt", summary: "format robots.txt files", run: runFmt},
+	{name: "lint", summary: "check robots.txt files for mistakes", run: runLint},
+	{name: "test", summary: "check a robots.txt against expectations files", run: runTest},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "-help" || args[0] == "help" {
+		usage(stderr)
+		return 2
+	}
+	for _, command := range commands {
+		if command.name == args[0] {
+			return command.run(args[1:], stdin, stdout, stderr)
+		}
+	}
+	fmt.Fprintf(stderr, "robotstxt: unknown command %q\n", args[0])
+	usage(stderr)
+	return 2
+}
+
+func usage(writer io.Writer) {
+	fmt.Fprintln(writer, "usage: robotstxt <command> [arguments]")
+	fmt.Fprintln(writer)
+	fmt.Fprintln(writer, "The commands are:")
+	fmt.Fprintln(writer)
+	for _, command := range commands {
+		fmt.Fprintf(writer, "\t%-11s%s\n", command.name, command.summary)
+	}
+}
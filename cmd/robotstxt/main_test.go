@@ -0,0 +1,496 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const unformatted = "User-agent : *\nDisallow:/cms/\nDisallow: /cms/\n"
+const formatted = "User-agent: *\nDisallow: /cms/\n"
+
+func TestRun_unknown_command(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 2, run([]string{"vet"}, strings.NewReader(""), &stdout, &stderr))
+	assert.Contains(t, stderr.String(), `unknown command "vet"`)
+	assert.Equal(t, 2, run(nil, strings.NewReader(""), &stdout, &stderr))
+}
+
+func TestRunFmt_standard_input(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 0, run([]string{"fmt"}, strings.NewReader(unformatted), &stdout, &stderr))
+	assert.Equal(t, formatted, stdout.String())
+
+	stdout.Reset()
+	assert.Equal(t, 0, run([]string{"fmt", "-l"}, strings.NewReader(unformatted), &stdout, &stderr))
+	assert.Equal(t, "<standard input>\n", stdout.String())
+
+	assert.Equal(t, 2, run([]string{"fmt", "-w"}, strings.NewReader(unformatted), &stdout, &stderr))
+	assert.Equal(t, 2, run([]string{"fmt"}, strings.NewReader("Disallow: /\xff"), &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "<standard input>:1:")
+}
+
+func TestRunFmt_files(t *testing.T) {
+	dir, err := ioutil.TempDir("", "robotstxt")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "www", "robots.txt")
+	assert.Nil(t, os.Mkdir(filepath.Dir(path), 0755))
+	assert.Nil(t, ioutil.WriteFile(path, []byte(unformatted), 0644))
+	other := filepath.Join(dir, "robots.staging.txt")
+	assert.Nil(t, ioutil.WriteFile(other, []byte(unformatted), 0600))
+
+	// Only files named robots.txt are found in a directory.
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 0, run([]string{"fmt", "-l", dir}, nil, &stdout, &stderr))
+	assert.Equal(t, path+"\n", stdout.String())
+
+	stdout.Reset()
+	assert.Equal(t, 0, run([]string{"fmt", "-d", path}, nil, &stdout, &stderr))
+	assert.Equal(t, "diff "+path+".orig "+path+"\n--- "+path+".orig\n+++ "+path+"\n"+`@@ -1,3 +1,2 @@
+-User-agent : *
+-Disallow:/cms/
++User-agent: *
+ Disallow: /cms/
+`, stdout.String())
+
+	stdout.Reset()
+	assert.Equal(t, 0, run([]string{"fmt", "-w", "-l", path, other}, nil, &stdout, &stderr))
+	assert.Equal(t, path+"\n"+other+"\n", stdout.String())
+	content, err := ioutil.ReadFile(other)
+	assert.Nil(t, err)
+	assert.Equal(t, formatted, string(content))
+	// The files are replaced in one step, with the same permissions and without leaving a temporary file behind.
+	info, err := os.Stat(other)
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	files, err := ioutil.ReadDir(dir)
+	assert.Nil(t, err)
+	assert.Len(t, files, 2)
+
+	stdout.Reset()
+	assert.Equal(t, 0, run([]string{"fmt", "-l", dir}, nil, &stdout, &stderr))
+	assert.Equal(t, "", stdout.String())
+
+	assert.Equal(t, 2, run([]string{"fmt", filepath.Join(dir, "missing")}, nil, &stdout, &stderr))
+}
+
+func TestWriteDiff(t *testing.T) {
+	from := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\nn\n"
+	to := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nn\no\n"
+	var stdout bytes.Buffer
+	writeDiff(&stdout, "from", "to", from, to)
+	assert.Equal(t, `--- from
++++ to
+@@ -1,5 +1,5 @@
+ a
+-b
++B
+ c
+ d
+ e
+@@ -10,5 +10,5 @@
+ j
+ k
+ l
+-m
+ n
++o
+`, stdout.String())
+
+	stdout.Reset()
+	writeDiff(&stdout, "from", "to", "", "a\n")
+	assert.Equal(t, "--- from\n+++ to\n@@ -0,0 +1,1 @@\n+a\n", stdout.String())
+}
+
+// The edits found are always the fewest that are needed.
+func TestDiffLines_shortest(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	randomLines := func() []string {
+		lines := make([]string, random.Intn(8))
+		for i := range lines {
+			lines[i] = string(rune('a' + random.Intn(3)))
+		}
+		return lines
+	}
+
+	for round := 0; round < 500; round++ {
+		from, to := randomLines(), randomLines()
+		var gotFrom, gotTo []string
+		edits := 0
+		for _, line := range diffLines(from, to) {
+			if line.kind != '+' {
+				gotFrom = append(gotFrom, line.text)
+			}
+			if line.kind != '-' {
+				gotTo = append(gotTo, line.text)
+			}
+			if line.kind != ' ' {
+				edits++
+			}
+		}
+		assert.Equal(t, strings.Join(from, "\n"), strings.Join(gotFrom, "\n"))
+		assert.Equal(t, strings.Join(to, "\n"), strings.Join(gotTo, "\n"))
+		assert.Equal(t, len(from)+len(to)-2*longestCommonSubsequence(from, to), edits, "%v %v", from, to)
+	}
+}
+
+// Files that have nothing in common take as many edits as they have lines, the time and memory a diff takes are capped instead of
+// growing with the edits.
+func TestDiffLines_large_files(t *testing.T) {
+	from, to := make([]string, 20000), make([]string, 20000)
+	for i := range from {
+		from[i], to[i] = fmt.Sprintf("Disallow: /a/%d", i), fmt.Sprintf("Disallow: /b/%d", i)
+	}
+
+	start := time.Now()
+	lines := diffLines(from, to)
+	assert.True(t, time.Since(start) < 5*time.Second, time.Since(start).String())
+	assert.Len(t, lines, 40000)
+	assert.Equal(t, diffLine{kind: '-', text: "Disallow: /a/0"}, lines[0])
+	assert.Equal(t, diffLine{kind: '+', text: "Disallow: /b/0"}, lines[20000])
+}
+
+func longestCommonSubsequence(from, to []string) int {
+	lengths := make([][]int, len(from)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(to)+1)
+	}
+	for i := len(from) - 1; i >= 0; i-- {
+		for j := len(to) - 1; j >= 0; j-- {
+			if from[i] == to[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else if lengths[i+1][j] > lengths[i][j+1] {
+				lengths[i][j] = lengths[i+1][j]
+			} else {
+				lengths[i][j] = lengths[i][j+1]
+			}
+		}
+	}
+	return lengths[0][0]
+}
+
+const robotsTxt = `User-agent: *
+Crawl-delay: 5
+Disallow: /cms/
+Allow: /cms/public/
+
+User-agent: AdsBot-Google
+Allow: /
+
+Sitemap: https://www.dumpsters.com/sitemap.xml
+`
+
+func TestRunCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "/robots.txt", request.URL.Path)
+		_, _ = writer.Write([]byte(robotsTxt))
+	}))
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 0, run([]string{"check", "googlebot", server.URL + "/products/", server.URL + "/cms/public/"}, nil, &stdout, &stderr))
+	assert.Equal(t, "allowed\t"+server.URL+"/products/\nallowed\t"+server.URL+"/cms/public/\n", stdout.String())
+
+	stdout.Reset()
+	assert.Equal(t, 1, run([]string{"check", "-json", "-f", "-", "googlebot", "/products/", "/cms/"}, strings.NewReader(robotsTxt), &stdout, &stderr))
+	var results []map[string]interface{}
+	assert.Nil(t, json.Unmarshal(stdout.Bytes(), &results))
+	assert.Equal(t, []map[string]interface{}{{"url": "/products/", "allowed": true}, {"url": "/cms/", "allowed": false}}, results)
+
+	// A URL of another origin than the robots.txt is an error.
+	stdout.Reset()
+	assert.Equal(t, 2, run([]string{"check", "-f", "-", "-url", "https://www.dumpsters.com", "googlebot", "https://www.budgetdumpster.com/"}, strings.NewReader(robotsTxt), &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "https://www.budgetdumpster.com/: absolute URL provided but the robot URL did not match")
+
+	assert.Equal(t, 2, run([]string{"check", "googlebot", "/products/"}, nil, &stdout, &stderr))
+	assert.Equal(t, 2, run([]string{"check", "googlebot"}, nil, &stdout, &stderr))
+	assert.Equal(t, 2, run([]string{"check", "-f", "missing.txt", "googlebot", "/"}, nil, &stdout, &stderr))
+}
+
+func TestRunExplain(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	args := []string{"explain", "-f", "-", "googlebot", "/cms/pages", "/cms/public/", "/products/"}
+	assert.Equal(t, 1, run(args, strings.NewReader(robotsTxt), &stdout, &stderr))
+	assert.Equal(t, `/cms/pages: disallowed by "Disallow: /cms/" in the group of "*"
+/cms/public/: allowed by "Allow: /cms/public/" in the group of "*"
+/products/: allowed, no rule of the group of "*" matches
+`, stdout.String())
+
+	stdout.Reset()
+	args = []string{"explain", "-f", "-", "-json", "adsbot-google", "/cms/"}
+	assert.Equal(t, 0, run(args, strings.NewReader(robotsTxt), &stdout, &stderr))
+	assert.JSONEq(t, `[{"url": "/cms/", "allowed": true, "agent": "AdsBot-Google", "rule": {"allow": true, "path": "/"}}]`, stdout.String())
+}
+
+func TestRunSubtree(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	args := []string{"subtree", "-f", "-", "googlebot", "/cms/", "/cms/pages/", "/products/"}
+	assert.Equal(t, 1, run(args, strings.NewReader(robotsTxt), &stdout, &stderr))
+	assert.Equal(t, `mixed	/cms/
+	allowed: /cms/public/
+	blocked: /cms/
+all blocked	/cms/pages/
+all allowed	/products/
+`, stdout.String())
+
+	stdout.Reset()
+	args = []string{"subtree", "-f", "-", "-json", "adsbot-google", "/cms/"}
+	assert.Equal(t, 0, run(args, strings.NewReader(robotsTxt), &stdout, &stderr))
+	assert.JSONEq(t, `[{"prefix": "/cms/", "status": "all allowed", "allowed": ["/cms/"], "blocked": []}]`, stdout.String())
+
+	args = []string{"subtree", "-f", "-", "-url", "https://www.dumpsters.com", "googlebot", "https://www.example.com/"}
+	assert.Equal(t, 2, run(args, strings.NewReader(robotsTxt), &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "robotstxt subtree: https://www.example.com/: ")
+}
+
+func TestRunMatrix(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	args := []string{"matrix", "-f", "-", "/cms/", "/cms/public/", "/products/"}
+	assert.Equal(t, 0, run(args, strings.NewReader(robotsTxt), &stdout, &stderr))
+	assert.Equal(t, `URL           *        AdsBot-Google
+/cms/         blocked  allowed
+/cms/public/  allowed  allowed
+/products/    allowed  allowed
+`, stdout.String())
+
+	stdout.Reset()
+	args = []string{"matrix", "-f", "-", "-agents", "googlebot,adsbot-google", "-json", "/cms/"}
+	assert.Equal(t, 0, run(args, strings.NewReader(robotsTxt), &stdout, &stderr))
+	assert.JSONEq(t, `[{"url": "/cms/", "allowed": ["adsbot-google"], "blocked": ["googlebot"]}]`, stdout.String())
+
+	stdout.Reset()
+	args = []string{"matrix", "-f", "-", "-url", "https://www.dumpsters.com", "https://www.example.com/", "/cms/"}
+	assert.Equal(t, 2, run(args, strings.NewReader(robotsTxt), &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "robotstxt matrix: https://www.example.com/: ")
+	assert.Equal(t, "URL    *        AdsBot-Google\n/cms/  blocked  allowed\n", stdout.String())
+}
+
+func TestRunSitemaps_delay_and_dump(t *testing.T) {
+	dir, err := ioutil.TempDir("", "robotstxt")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "robots.txt")
+	assert.Nil(t, ioutil.WriteFile(path, []byte(robotsTxt), 0644))
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 0, run([]string{"sitemaps", path}, nil, &stdout, &stderr))
+	assert.Equal(t, "https://www.dumpsters.com/sitemap.xml\n", stdout.String())
+
+	stdout.Reset()
+	assert.Equal(t, 0, run([]string{"sitemaps", "-json"}, strings.NewReader("User-agent: *\nDisallow: /\n"), &stdout, &stderr))
+	assert.JSONEq(t, `[]`, stdout.String())
+
+	stdout.Reset()
+	assert.Equal(t, 0, run([]string{"delay", "googlebot", path}, nil, &stdout, &stderr))
+	assert.Equal(t, "5\n", stdout.String())
+
+	stdout.Reset()
+	assert.Equal(t, 0, run([]string{"delay", "-json", "adsbot-google", "-"}, strings.NewReader(robotsTxt), &stdout, &stderr))
+	assert.JSONEq(t, `{"agent": "adsbot-google", "crawlDelay": 0}`, stdout.String())
+
+	stdout.Reset()
+	assert.Equal(t, 0, run([]string{"dump", path}, nil, &stdout, &stderr))
+	assert.JSONEq(t, `{
+		"groups": [
+			{"agents": ["*"], "crawlDelay": 5, "rules": [{"allow": false, "path": "/cms/"}, {"allow": true, "path": "/cms/public/"}]},
+			{"agents": ["AdsBot-Google"], "crawlDelay": 0, "rules": [{"allow": true, "path": "/"}]}
+		],
+		"sitemaps": ["https://www.dumpsters.com/sitemap.xml"]
+	}`, stdout.String())
+
+	assert.Equal(t, 2, run([]string{"delay"}, nil, &stdout, &stderr))
+	assert.Equal(t, 2, run([]string{"dump", path, path}, nil, &stdout, &stderr))
+	assert.Equal(t, 2, run([]string{"dump"}, strings.NewReader("User-agent: *\nCrawl-delay: soon\n"), &stdout, &stderr))
+}
+
+func TestRunLint(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 0, run([]string{"lint"}, strings.NewReader(robotsTxt), &stdout, &stderr))
+	assert.Equal(t, "", stdout.String())
+
+	source := "Disallow: /cms/\nUser-agent: *\nDisallow: cms/\n"
+	assert.Equal(t, 1, run([]string{"lint", "-"}, strings.NewReader(source), &stdout, &stderr))
+	assert.Equal(t, `<standard input>:1: warning RT001: Disallow before any User-agent is ignored
+	fix: add a User-agent line above it
+<standard input>:3: error RT002: path "cms/" does not start with "/" or "*" so it never matches
+	fix: use "Disallow: /cms/"
+`, stdout.String())
+
+	stdout.Reset()
+	assert.Equal(t, 1, run([]string{"lint", "-format", "json"}, strings.NewReader(source), &stdout, &stderr))
+	var results []map[string]interface{}
+	assert.Nil(t, json.Unmarshal(stdout.Bytes(), &results))
+	assert.Len(t, results, 2)
+	assert.Equal(t, map[string]interface{}{"file": "<standard input>", "line": float64(3), "code": "RT002", "severity": "error",
+		"message": `path "cms/" does not start with "/" or "*" so it never matches`, "fix": `use "Disallow: /cms/"`}, results[1])
+
+	stdout.Reset()
+	assert.Equal(t, 1, run([]string{"lint", "-format", "sarif"}, strings.NewReader(source), &stdout, &stderr))
+	var sarif struct {
+		Version string
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Name  string
+					Rules []struct{ ID string }
+				}
+			}
+			Results []struct {
+				RuleID    string
+				Level     string
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct{ URI string }
+						Region           struct{ StartLine int }
+					}
+				}
+			}
+		}
+	}
+	assert.Nil(t, json.Unmarshal(stdout.Bytes(), &sarif))
+	assert.Equal(t, "2.1.0", sarif.Version)
+	assert.Equal(t, "robotstxt", sarif.Runs[0].Tool.Driver.Name)
+	assert.Equal(t, "RT001", sarif.Runs[0].Tool.Driver.Rules[0].ID)
+	assert.Equal(t, "RT002", sarif.Runs[0].Results[1].RuleID)
+	assert.Equal(t, "error", sarif.Runs[0].Results[1].Level)
+	assert.Equal(t, "<standard input>", sarif.Runs[0].Results[1].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, 3, sarif.Runs[0].Results[1].Locations[0].PhysicalLocation.Region.StartLine)
+
+	assert.Equal(t, 2, run([]string{"lint", "-format", "xml"}, strings.NewReader(source), &stdout, &stderr))
+	assert.Equal(t, 2, run([]string{"lint", "missing.txt"}, nil, &stdout, &stderr))
+}
+
+func TestRunLint_fetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		_, _ = writer.Write([]byte("User-agent: *\nCrawl-delay: 1.5\n"))
+	}))
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 1, run([]string{"lint", server.URL}, nil, &stdout, &stderr))
+	assert.Equal(t, server.URL+`:2: error RT007: crawl-delay "1.5" is not a whole number of seconds
+	fix: round it up to whole seconds, "Crawl-delay: 2"
+`, stdout.String())
+
+	missing := httptest.NewServer(http.NotFoundHandler())
+	defer missing.Close()
+	assert.Equal(t, 2, run([]string{"lint", missing.URL}, nil, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "no robots.txt at "+missing.URL+"/robots.txt, the status code is 404")
+}
+
+func TestRunDiff(t *testing.T) {
+	dir, err := ioutil.TempDir("", "robotstxt")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "robots.txt")
+	assert.Nil(t, ioutil.WriteFile(path, []byte(robotsTxt), 0644))
+
+	newer := "User-agent: *\nCrawl-delay: 5\nDisallow: /cms/\n\nUser-agent: AdsBot-Google\nAllow: /\n\nSitemap: https://www.dumpsters.com/sitemap.xml\n"
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 1, run([]string{"diff", path, "-"}, strings.NewReader(newer), &stdout, &stderr))
+	assert.Equal(t, `*: /cms/public/ and similar paths are blocked by "Disallow: /cms/", were allowed by "Allow: /cms/public/"
+`, stdout.String())
+
+	stdout.Reset()
+	assert.Equal(t, 0, run([]string{"diff", "-json", "-", path}, strings.NewReader(newer), &stdout, &stderr))
+	assert.JSONEq(t, `{
+		"agents": [{
+			"agent": "*",
+			"blocked": [],
+			"allowed": [{"oldRule": {"allow": false, "path": "/cms/"}, "newRule": {"allow": true, "path": "/cms/public/"}, "example": "/cms/public/"}],
+			"oldCrawlDelay": 5,
+			"newCrawlDelay": 5
+		}],
+		"addedSitemaps": [],
+		"removedSitemaps": [],
+		"moreRestrictive": false
+	}`, stdout.String())
+
+	stdout.Reset()
+	slower := strings.Replace(strings.Replace(robotsTxt, "Crawl-delay: 5", "Crawl-delay: 10", 1), "sitemap.xml", "sitemap-index.xml", 1)
+	assert.Equal(t, 1, run([]string{"diff", path, "-"}, strings.NewReader(slower), &stdout, &stderr))
+	assert.Equal(t, `*: crawl-delay 5s -> 10s
+sitemap added: https://www.dumpsters.com/sitemap-index.xml
+sitemap removed: https://www.dumpsters.com/sitemap.xml
+`, stdout.String())
+
+	stdout.Reset()
+	assert.Equal(t, 0, run([]string{"diff", path, path}, nil, &stdout, &stderr))
+	assert.Equal(t, "", stdout.String())
+
+	assert.Equal(t, 2, run([]string{"diff", "-", "-"}, strings.NewReader(newer), &stdout, &stderr))
+	assert.Equal(t, 2, run([]string{"diff", path}, nil, &stdout, &stderr))
+	assert.Equal(t, 2, run([]string{"diff", path, "missing.txt"}, nil, &stdout, &stderr))
+}
+
+func TestRunEquivalent_and_minimize(t *testing.T) {
+	dir, err := ioutil.TempDir("", "robotstxt")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "robots.txt")
+	redundant := strings.Replace(robotsTxt, "Disallow: /cms/\n", "Disallow: /cms/\nDisallow: /cms/pages/\nDisallow: /cms/\n", 1)
+	assert.Nil(t, ioutil.WriteFile(path, []byte(redundant), 0644))
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 0, run([]string{"equivalent", path, "-"}, strings.NewReader(robotsTxt), &stdout, &stderr))
+	assert.Equal(t, "equivalent\n", stdout.String())
+
+	stdout.Reset()
+	assert.Equal(t, 1, run([]string{"equivalent", path, "-"}, strings.NewReader("User-agent: *\nDisallow: /cms/\n"), &stdout, &stderr))
+	assert.Equal(t, "not equivalent\n", stdout.String())
+
+	stdout.Reset()
+	args := []string{"equivalent", "-agents", "googlebot,bingbot", path, "-"}
+	assert.Equal(t, 0, run(args, strings.NewReader("User-agent: *\nDisallow: /cms/\nAllow: /cms/public/\n"), &stdout, &stderr))
+
+	stdout.Reset()
+	assert.Equal(t, 0, run([]string{"minimize", path}, nil, &stdout, &stderr))
+	assert.Equal(t, strings.Replace(robotsTxt, "Allow: /\n\nSitemap", "Disallow:\n\nSitemap", 1), stdout.String())
+
+	assert.Equal(t, 2, run([]string{"equivalent", path}, nil, &stdout, &stderr))
+	assert.Equal(t, 2, run([]string{"minimize", "missing.txt"}, nil, &stdout, &stderr))
+}
+
+func TestRunTest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "robotstxt")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	expectations := filepath.Join(dir, "expectations.txt")
+	assert.Nil(t, ioutil.WriteFile(expectations, []byte(`googlebot     /cms/public/*  allow
+*             /cms/*         deny  # Nothing in the CMS is public
+adsbot-google /cms/          allow
+`), 0644))
+	junit := filepath.Join(dir, "report.xml")
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 1, run([]string{"test", "-junit", junit, "-", expectations}, strings.NewReader(robotsTxt), &stdout, &stderr))
+	assert.Equal(t, expectations+`:2: * /cms/* deny: * can crawl /cms/public/, it is allowed by "Allow: /cms/public/" in the group of "*" `+
+		`(Nothing in the CMS is public)
+2 passed, 1 failed
+`, stdout.String())
+	report, err := ioutil.ReadFile(junit)
+	assert.Nil(t, err)
+	assert.Contains(t, string(report), `<testsuite name="`+expectations+`" tests="3" failures="1" errors="0">`)
+
+	stdout.Reset()
+	assert.Nil(t, ioutil.WriteFile(expectations, []byte("googlebot https://www.example.com/ allow\n"), 0644))
+	args := []string{"test", "-url", "https://www.dumpsters.com", "-", expectations}
+	assert.Equal(t, 2, run(args, strings.NewReader(robotsTxt), &stdout, &stderr))
+	assert.Contains(t, stderr.String(), expectations+":1: googlebot https://www.example.com/ allow: ")
+
+	stderr.Reset()
+	assert.Nil(t, ioutil.WriteFile(expectations, []byte("googlebot /cms/\n"), 0644))
+	assert.Equal(t, 2, run([]string{"test", "-", expectations}, strings.NewReader(robotsTxt), &stdout, &stderr))
+	assert.Equal(t, "robotstxt test: "+expectations+": line 1: expected an agent, a URL, and allow or deny but found 2 fields\n", stderr.String())
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"github.com/itmayziii/robotstxt/v2"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+type matrixRow struct {
+	URL     string   `json:"url"`
+	Allowed []string `json:"allowed"`
+	Blocked []string `json:"blocked"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// runMatrix shows which agents can crawl which URLs, a row for every URL and a column for every agent.
+func runMatrix(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := newFlagSet("matrix", "[-f source] [-url url] [-agents agent,...] [-json] <url ...>", stderr)
+	source := flags.String("f", "", "read the robots.txt from a file, an http(s) URL, or standard input with \"-\" instead of fetching it for the first URL")
+	url := flags.String("url", "", "URL the robots.txt applies to when it is read from a file or standard input, the first absolute URL is used by default")
+	agentList := flags.String("agents", "", "comma separated agents to show, every agent that is named in the robots.txt when empty")
+	asJSON := flags.Bool("json", false, "write the rows as JSON")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+	robotsTxt := loadRobotsTxtForURLs("matrix", *source, *url, flags.Args(), stdin, stderr)
+	if robotsTxt == nil {
+		return 2
+	}
+
+	agents := namedAgents(robotsTxt)
+	if *agentList != "" {
+		agents = strings.Split(*agentList, ",")
+	}
+	var rows []matrixRow
+	for _, url := range flags.Args() {
+		if *agentList == "" {
+			rows = append(rows, namedAgentsRow(robotsTxt, url))
+			continue
+		}
+
+		row := matrixRow{URL: url, Allowed: []string{}, Blocked: []string{}}
+		for _, agent := range agents {
+			canCrawl, err := robotsTxt.CanCrawl(agent, url)
+			if err != nil {
+				row = matrixRow{URL: url, Error: err.Error()}
+				break
+			}
+			if canCrawl {
+				row.Allowed = append(row.Allowed, agent)
+			} else {
+				row.Blocked = append(row.Blocked, agent)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	exitCode := 0
+	for _, row := range rows {
+		if row.Error != "" {
+			fmt.Fprintf(stderr, "robotstxt matrix: %s: %s\n", row.URL, row.Error)
+			exitCode = 2
+		}
+	}
+	if *asJSON {
+		_ = writeJSON(stdout, rows)
+		return exitCode
+	}
+
+	table := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(table, "URL\t%s\n", strings.Join(agents, "\t"))
+	for _, row := range rows {
+		if row.Error != "" {
+			continue
+		}
+		cells := make([]string, len(agents))
+		for i, agent := range agents {
+			cells[i] = "blocked"
+			for _, allowed := range row.Allowed {
+				if allowed == agent {
+					cells[i] = "allowed"
+				}
+			}
+		}
+		fmt.Fprintf(table, "%s\t%s\n", row.URL, strings.Join(cells, "\t"))
+	}
+	_ = table.Flush()
+	return exitCode
+}
+
+// namedAgentsRow is the row of a URL for the agents that are named in the robots.txt.
+func namedAgentsRow(robotsTxt *robotstxt.RobotsTxt, url string) matrixRow {
+	allowed, err := robotsTxt.AgentsAllowed(url)
+	if err != nil {
+		return matrixRow{URL: url, Error: err.Error()}
+	}
+	blocked, err := robotsTxt.AgentsBlocked(url)
+	if err != nil {
+		return matrixRow{URL: url, Error: err.Error()}
+	}
+	return matrixRow{URL: url, Allowed: allowed, Blocked: blocked}
+}
+
+// namedAgents returns the agents that are named in the robots.txt, sorted by name and written the way the robots.txt writes them.
+func namedAgents(robotsTxt *robotstxt.RobotsTxt) []string {
+	var agents []string
+	seen := make(map[string]bool)
+	for _, group := range robotsTxt.Groups() {
+		for _, agent := range group.Agents {
+			if agent != "" && !seen[agent] {
+				seen[agent] = true
+				agents = append(agents, agent)
+			}
+		}
+	}
+	sort.Strings(agents)
+	return agents
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"github.com/itmayziii/robotstxt/v2"
+	"io"
+	"strings"
+)
+
+// runEquivalent reports whether two robots.txt files allow the same paths, the exit code is 1 when they do not.
+func runEquivalent(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := newFlagSet("equivalent", "[-agents agent,...] <source> <source>", stderr)
+	agents := flags.String("agents", "", "comma separated agents to compare, every agent that is named and \"*\" when empty")
+	robotsTxts, exitCode := parseSourcePairArgs("equivalent", flags, args, stdin, stderr)
+	if robotsTxts == nil {
+		return exitCode
+	}
+
+	var agentList []string
+	if *agents != "" {
+		agentList = strings.Split(*agents, ",")
+	}
+	equivalent, err := robotstxt.Equivalent(robotsTxts[0], robotsTxts[1], agentList)
+	if err != nil {
+		fmt.Fprintf(stderr, "robotstxt equivalent: %v\n", err)
+		return 2
+	}
+	if !equivalent {
+		fmt.Fprintln(stdout, "not equivalent")
+		return 1
+	}
+	fmt.Fprintln(stdout, "equivalent")
+	return 0
+}
+
+// runMinimize writes a robots.txt without the rules that never decide whether a path can be crawled.
+func runMinimize(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := newFlagSet("minimize", "[source]", stderr)
+	robotsTxt, exitCode := parseSourceArgs("minimize", flags, args, 0, stdin, stderr)
+	if robotsTxt == nil {
+		return exitCode
+	}
+
+	minimized, err := robotstxt.Minimize(robotsTxt)
+	if err != nil {
+		fmt.Fprintf(stderr, "robotstxt minimize: %v\n", err)
+		return 2
+	}
+	_, _ = minimized.WriteTo(stdout)
+	return 0
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/itmayziii/robotstxt/v2"
+	"io"
+	"strconv"
+)
+
+type jsonReport struct {
+	Agents          []jsonAgentChanges `json:"agents"`
+	AddedSitemaps   []string           `json:"addedSitemaps"`
+	RemovedSitemaps []string           `json:"removedSitemaps"`
+	MoreRestrictive bool               `json:"moreRestrictive"`
+}
+
+type jsonAgentChanges struct {
+	Agent         string           `json:"agent"`
+	Blocked       []jsonPathChange `json:"blocked"`
+	Allowed       []jsonPathChange `json:"allowed"`
+	OldCrawlDelay float64          `json:"oldCrawlDelay"` // Seconds.
+	NewCrawlDelay float64          `json:"newCrawlDelay"` // Seconds.
+}
+
+type jsonPathChange struct {
+	OldRule *jsonRule `json:"oldRule"`
+	NewRule *jsonRule `json:"newRule"`
+	Example string    `json:"example"`
+}
+
+// runDiff reports what changed for crawlers between two robots.txt files, the exit code is 1 when the new one is more restrictive.
+func runDiff(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := newFlagSet("diff", "[-json] <old source> <new source>", stderr)
+	asJSON := flags.Bool("json", false, "write the changes as JSON")
+	robotsTxts, exitCode := parseSourcePairArgs("diff", flags, args, stdin, stderr)
+	if robotsTxts == nil {
+		return exitCode
+	}
+
+	report, err := robotstxt.Diff(robotsTxts[0], robotsTxts[1])
+	if err != nil {
+		fmt.Fprintf(stderr, "robotstxt diff: %v\n", err)
+		return 2
+	}
+	if report.MoreRestrictive() {
+		exitCode = 1
+	}
+	if *asJSON {
+		_ = writeJSON(stdout, newJSONReport(report))
+		return exitCode
+	}
+
+	for _, agent := range report.Agents {
+		for _, change := range agent.Blocked {
+			fmt.Fprintf(stdout, "%s: %s and similar paths are %s, were %s\n", agent.Agent, change.Example, describeRule(false, change.NewRule),
+				describeRule(true, change.OldRule))
+		}
+		for _, change := range agent.Allowed {
+			fmt.Fprintf(stdout, "%s: %s and similar paths are %s, were %s\n", agent.Agent, change.Example, describeRule(true, change.NewRule),
+				describeRule(false, change.OldRule))
+		}
+		if agent.OldCrawlDelay != agent.NewCrawlDelay {
+			fmt.Fprintf(stdout, "%s: crawl-delay %s -> %s\n", agent.Agent, agent.OldCrawlDelay, agent.NewCrawlDelay)
+		}
+	}
+	for _, sitemap := range report.AddedSitemaps {
+		fmt.Fprintf(stdout, "sitemap added: %s\n", sitemap)
+	}
+	for _, sitemap := range report.RemovedSitemaps {
+		fmt.Fprintf(stdout, "sitemap removed: %s\n", sitemap)
+	}
+	return exitCode
+}
+
+// parseSourcePairArgs parses the flags of a command that compares two sources and reads both robots.txt files, they are nil when the
+// command has to exit.
+func parseSourcePairArgs(name string, flags *flag.FlagSet, args []string, stdin io.Reader, stderr io.Writer) ([]*robotstxt.RobotsTxt, int) {
+	if err := flags.Parse(args); err != nil {
+		return nil, 2
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return nil, 2
+	}
+	if flags.Arg(0) == "-" && flags.Arg(1) == "-" {
+		fmt.Fprintf(stderr, "robotstxt %s: only one source can be standard input\n", name)
+		return nil, 2
+	}
+
+	var robotsTxts []*robotstxt.RobotsTxt
+	for _, source := range flags.Args() {
+		robotsTxt, err := loadRobotsTxt(source, "", stdin)
+		if err != nil {
+			fmt.Fprintf(stderr, "robotstxt %s: %s: %v\n", name, source, err)
+			return nil, 2
+		}
+		robotsTxts = append(robotsTxts, robotsTxt)
+	}
+	return robotsTxts, 0
+}
+
+// describeRule describes why paths are allowed or blocked, i.e. `blocked by "Disallow: /cms/"`.
+func describeRule(allowed bool, rule *robotstxt.Rule) string {
+	switch {
+	case rule != nil && allowed:
+		return "allowed by " + strconv.Quote(rule.String())
+	case rule != nil:
+		return "blocked by " + strconv.Quote(rule.String())
+	}
+	return "allowed because no rule matches"
+}
+
+func newJSONReport(report robotstxt.Report) jsonReport {
+	jsonReport := jsonReport{Agents: []jsonAgentChanges{}, AddedSitemaps: report.AddedSitemaps, RemovedSitemaps: report.RemovedSitemaps,
+		MoreRestrictive: report.MoreRestrictive()}
+	if jsonReport.AddedSitemaps == nil {
+		jsonReport.AddedSitemaps = []string{}
+	}
+	if jsonReport.RemovedSitemaps == nil {
+		jsonReport.RemovedSitemaps = []string{}
+	}
+	for _, agent := range report.Agents {
+		jsonReport.Agents = append(jsonReport.Agents, jsonAgentChanges{
+			Agent:         agent.Agent,
+			Blocked:       newJSONPathChanges(agent.Blocked),
+			Allowed:       newJSONPathChanges(agent.Allowed),
+			OldCrawlDelay: agent.OldCrawlDelay.Seconds(),
+			NewCrawlDelay: agent.NewCrawlDelay.Seconds(),
+		})
+	}
+	return jsonReport
+}
+
+func newJSONPathChanges(changes []robotstxt.PathChange) []jsonPathChange {
+	jsonChanges := []jsonPathChange{}
+	for _, change := range changes {
+		jsonChanges = append(jsonChanges, jsonPathChange{OldRule: newJSONRule(change.OldRule), NewRule: newJSONRule(change.NewRule),
+			Example: change.Example})
+	}
+	return jsonChanges
+}
+
+func newJSONRule(rule *robotstxt.Rule) *jsonRule {
+	if rule == nil {
+		return nil
+	}
+	return &jsonRule{Allow: rule.Allow, Path: rule.Path}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/itmayziii/robotstxt/v2"
+	"io"
+	netUrl "net/url"
+	"strings"
+	"time"
+)
+
+// fetchTimeout is how long fetching a robots.txt over HTTP may take.
+const fetchTimeout = 30 * time.Second
+
+// defaultURL is the URL of a robots.txt that is read from a file or standard input when nothing tells where it is from.
+const defaultURL = "http://localhost"
+
+// loadRobotsTxt reads a robots.txt from standard input when the source is "-" or empty, fetches it when the source is an http(s)
+// URL, and reads it from a file otherwise. The URL is where the rules apply to when the robots.txt is not fetched.
+func loadRobotsTxt(source, url string, stdin io.Reader) (*robotstxt.RobotsTxt, error) {
+	if url == "" {
+		url = defaultURL
+	}
+	switch {
+	case source == "" || source == "-":
+		return robotstxt.New(url, stdin)
+	case isHTTP(source):
+		ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+		defer cancel()
+		fetcher := &robotstxt.Fetcher{UserAgent: "robotstxt"}
+		return fetcher.Get(ctx, source)
+	default:
+		return robotstxt.NewFromFile(url, source)
+	}
+}
+
+func isHTTP(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+// firstAbsoluteURL returns the first URL that has a scheme and host, or an empty string.
+func firstAbsoluteURL(urls []string) string {
+	for _, url := range urls {
+		parsedUrl, err := netUrl.Parse(url)
+		if err == nil && parsedUrl.IsAbs() && parsedUrl.Host != "" {
+			return url
+		}
+	}
+	return ""
+}
+
+func newFlagSet(name, usage string, stderr io.Writer) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: robotstxt "+name+" "+usage)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+func writeJSON(writer io.Writer, value interface{}) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"github.com/itmayziii/robotstxt/v2"
+	"io"
+	"strings"
+)
+
+type subtreeResult struct {
+	Prefix  string   `json:"prefix"`
+	Status  string   `json:"status,omitempty"`
+	Allowed []string `json:"allowed"`
+	Blocked []string `json:"blocked"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// runSubtree reports whether an agent can crawl every path under prefixes, the exit code is 1 when a path under one of them can not
+// be crawled.
+func runSubtree(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	options, exitCode := parseCheckArgs("subtree", "[-f source] [-url url] [-json] <agent> <prefix ...>", args, stdin, stderr)
+	if options == nil {
+		return exitCode
+	}
+
+	var results []subtreeResult
+	for _, prefix := range options.urls {
+		subtree, err := options.robotsTxt.SubtreeStatus(options.agent, prefix)
+		result := subtreeResult{Prefix: prefix, Allowed: subtree.Allowed, Blocked: subtree.Blocked}
+		if result.Allowed == nil {
+			result.Allowed = []string{}
+		}
+		if result.Blocked == nil {
+			result.Blocked = []string{}
+		}
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Status = subtree.Status.String()
+		}
+		results = append(results, result)
+	}
+
+	if options.json {
+		_ = writeJSON(stdout, results)
+	}
+	exitCode = 0
+	for _, result := range results {
+		if result.Error != "" {
+			fmt.Fprintf(stderr, "robotstxt subtree: %s: %s\n", result.Prefix, result.Error)
+			exitCode = 2
+			continue
+		}
+		if result.Status != robotstxt.AllAllowed.String() && exitCode == 0 {
+			exitCode = 1
+		}
+		if options.json {
+			continue
+		}
+
+		fmt.Fprintf(stdout, "%s\t%s\n", result.Status, result.Prefix)
+		if result.Status == robotstxt.Mixed.String() {
+			fmt.Fprintf(stdout, "\tallowed: %s\n\tblocked: %s\n", strings.Join(result.Allowed, " "), strings.Join(result.Blocked, " "))
+		}
+	}
+	return exitCode
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"github.com/itmayziii/robotstxt/v2/robotstxttest"
+	"io"
+	"os"
+)
+
+// runTest checks a robots.txt against expectations files, the exit code is 1 when an expectation is not met.
+func runTest(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := newFlagSet("test", "[-url url] [-junit file] <source> <expectations ...>", stderr)
+	url := flags.String("url", "", "URL the robots.txt applies to when it is read from a file or standard input")
+	junit := flags.String("junit", "", "also write the results as JUnit XML to a file")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() < 2 {
+		flags.Usage()
+		return 2
+	}
+
+	robotsTxt, err := loadRobotsTxt(flags.Arg(0), *url, stdin)
+	if err != nil {
+		fmt.Fprintf(stderr, "robotstxt test: %s: %v\n", flags.Arg(0), err)
+		return 2
+	}
+
+	var suites []robotstxttest.Suite
+	for _, path := range flags.Args()[1:] {
+		expectations, err := robotstxttest.ParseFile(path)
+		if err != nil {
+			fmt.Fprintf(stderr, "robotstxt test: %s: %v\n", path, err)
+			return 2
+		}
+		suites = append(suites, robotstxttest.Suite{Name: path, Results: robotstxttest.Run(robotsTxt, expectations)})
+	}
+
+	exitCode, passed, failed := 0, 0, 0
+	for _, suite := range suites {
+		for _, result := range suite.Results {
+			switch {
+			case result.Err != nil:
+				fmt.Fprintf(stderr, "%s:%d: %s\n", suite.Name, result.Expectation.Line, result)
+				exitCode = 2
+			case !result.Passed:
+				fmt.Fprintf(stdout, "%s:%d: %s\n", suite.Name, result.Expectation.Line, result)
+				failed++
+				if exitCode == 0 {
+					exitCode = 1
+				}
+			default:
+				passed++
+			}
+		}
+	}
+	fmt.Fprintf(stdout, "%d passed, %d failed\n", passed, failed)
+
+	if *junit != "" {
+		if err := writeJUnitFile(*junit, suites); err != nil {
+			fmt.Fprintf(stderr, "robotstxt test: %v\n", err)
+			return 2
+		}
+	}
+	return exitCode
+}
+
+func writeJUnitFile(path string, suites []robotstxttest.Suite) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := robotstxttest.WriteJUnit(file, suites...); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
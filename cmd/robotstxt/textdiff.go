@@ -0,0 +1,215 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines around a change.
+const diffContext = 3
+
+// maxDiffSteps is how far the searches from either end go before a part of the texts is shown as replaced as a whole, the time a
+// diff takes grows with the lines times the steps.
+const maxDiffSteps = 1000
+
+type diffLine struct {
+	kind byte // ' ' for a line that is in both texts, '-' for a removed line, '+' for an added line.
+	text string
+}
+
+// writeDiff writes the changes from one text to another in the unified diff format.
+func writeDiff(writer io.Writer, fromName, toName, from, to string) {
+	lines := diffLines(splitLines(from), splitLines(to))
+
+	fmt.Fprintf(writer, "--- %s\n+++ %s\n", fromName, toName)
+	fromLine, toLine := 0, 0 // Lines before the current position.
+	for start := 0; start < len(lines); {
+		if lines[start].kind == ' ' {
+			fromLine++
+			toLine++
+			start++
+			continue
+		}
+
+		// A hunk continues as long as changes are close enough to share context.
+		end := start
+		for i := start; i < len(lines) && i-end <= 2*diffContext; i++ {
+			if lines[i].kind != ' ' {
+				end = i + 1
+			}
+		}
+		before := start - diffContext
+		if before < 0 {
+			before = 0
+		}
+		after := end + diffContext
+		if after > len(lines) {
+			after = len(lines)
+		}
+
+		hunkFrom, hunkTo := fromLine-(start-before), toLine-(start-before)
+		fromCount, toCount := 0, 0
+		for _, line := range lines[before:after] {
+			if line.kind != '+' {
+				fromCount++
+			}
+			if line.kind != '-' {
+				toCount++
+			}
+		}
+		fmt.Fprintf(writer, "@@ -%s +%s @@\n", hunkRange(hunkFrom, fromCount), hunkRange(hunkTo, toCount))
+		for _, line := range lines[before:after] {
+			fmt.Fprintf(writer, "%c%s\n", line.kind, line.text)
+		}
+
+		for _, line := range lines[start:after] {
+			if line.kind != '+' {
+				fromLine++
+			}
+			if line.kind != '-' {
+				toLine++
+			}
+		}
+		start = after
+	}
+}
+
+func hunkRange(line, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", line)
+	}
+	return fmt.Sprintf("%d,%d", line+1, count)
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// diffLines finds the shortest edit from one list of lines to another with the linear space variant of Myers' algorithm, it splits
+// the lists at the middle of the edit and diffs both halves instead of keeping every step of the search around. Lists that need
+// more than about 2*maxDiffSteps edits get one that is not always the shortest.
+func diffLines(from, to []string) []diffLine {
+	lines := appendDiffLines(nil, from, to)
+
+	// The halves can interleave removed and added lines, every change lists the removed ones first.
+	for start := 0; start < len(lines); start++ {
+		end := start
+		for end < len(lines) && lines[end].kind != ' ' {
+			end++
+		}
+		sort.SliceStable(lines[start:end], func(i, j int) bool {
+			return lines[start+i].kind == '-' && lines[start+j].kind == '+'
+		})
+		start = end
+	}
+	return lines
+}
+
+func appendDiffLines(lines []diffLine, from, to []string) []diffLine {
+	for len(from) > 0 && len(to) > 0 && from[0] == to[0] {
+		lines = append(lines, diffLine{kind: ' ', text: from[0]})
+		from, to = from[1:], to[1:]
+	}
+	common := 0
+	for common < len(from) && common < len(to) && from[len(from)-1-common] == to[len(to)-1-common] {
+		common++
+	}
+	suffix := from[len(from)-common:]
+	from, to = from[:len(from)-common], to[:len(to)-common]
+
+	switch {
+	case len(from) == 0:
+		for _, text := range to {
+			lines = append(lines, diffLine{kind: '+', text: text})
+		}
+	case len(to) == 0:
+		for _, text := range from {
+			lines = append(lines, diffLine{kind: '-', text: text})
+		}
+	default:
+		// The first and last lines differ so the edit has at least two steps and both halves are smaller than the whole.
+		startX, startY, endX, endY, found := middleSnake(from, to)
+		if !found {
+			for _, text := range from {
+				lines = append(lines, diffLine{kind: '-', text: text})
+			}
+			for _, text := range to {
+				lines = append(lines, diffLine{kind: '+', text: text})
+			}
+			break
+		}
+		lines = appendDiffLines(lines, from[:startX], to[:startY])
+		for _, text := range from[startX:endX] {
+			lines = append(lines, diffLine{kind: ' ', text: text})
+		}
+		lines = appendDiffLines(lines, from[endX:], to[endY:])
+	}
+
+	for _, text := range suffix {
+		lines = append(lines, diffLine{kind: ' ', text: text})
+	}
+	return lines
+}
+
+// middleSnake searches for the shortest edit from both ends at once and returns the unchanged lines, the snake, where the two
+// searches meet. A shortest edit goes through the snake with half of its steps on either side, found is false when the searches
+// did not meet within maxDiffSteps.
+func middleSnake(from, to []string) (startX, startY, endX, endY int, found bool) {
+	delta := len(from) - len(to)
+	odd := delta%2 != 0
+	max := (len(from)+len(to)+1)/2 + 1
+	if max > maxDiffSteps {
+		max = maxDiffSteps
+	}
+	offset := max + 1
+	forward := make([]int, 2*max+3)  // The furthest line of from reached on every diagonal from the start.
+	backward := make([]int, 2*max+3) // The same from the end, counting lines from the end.
+
+	for edits := 0; edits <= max; edits++ {
+		for diagonal := -edits; diagonal <= edits; diagonal += 2 {
+			x := furthestStep(forward, offset, diagonal, edits)
+			y := x - diagonal
+			snakeX, snakeY := x, y
+			for x < len(from) && y < len(to) && from[x] == to[y] {
+				x++
+				y++
+			}
+			forward[offset+diagonal] = x
+
+			other := delta - diagonal
+			if odd && other >= -(edits-1) && other <= edits-1 && x+backward[offset+other] >= len(from) {
+				return snakeX, snakeY, x, y, true
+			}
+		}
+
+		for diagonal := -edits; diagonal <= edits; diagonal += 2 {
+			x := furthestStep(backward, offset, diagonal, edits)
+			y := x - diagonal
+			snakeX, snakeY := x, y
+			for x < len(from) && y < len(to) && from[len(from)-1-x] == to[len(to)-1-y] {
+				x++
+				y++
+			}
+			backward[offset+diagonal] = x
+
+			other := delta - diagonal
+			if !odd && other >= -edits && other <= edits && x+forward[offset+other] >= len(from) {
+				return len(from) - x, len(to) - y, len(from) - snakeX, len(to) - snakeY, true
+			}
+		}
+	}
+	return 0, 0, 0, 0, false
+}
+
+// furthestStep is where a search with one more step starts on a diagonal, from the neighboring diagonal that got the furthest.
+func furthestStep(furthest []int, offset, diagonal, edits int) int {
+	if diagonal == -edits || (diagonal != edits && furthest[offset+diagonal-1] < furthest[offset+diagonal+1]) {
+		return furthest[offset+diagonal+1]
+	}
+	return furthest[offset+diagonal-1] + 1
+}
@@ -0,0 +1,186 @@
+package robotstxt
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Report is what changed for crawlers between two versions of a robots.txt, see Diff.
+type Report struct {
+	// Agents are the robots (user-agents) something changed for, sorted by name. The robots that are not named in either robots.txt
+	// are reported as "*".
+	Agents []AgentChanges
+
+	// AddedSitemaps are the sitemaps of the new robots.txt that the old one did not have.
+	AddedSitemaps []string
+
+	// RemovedSitemaps are the sitemaps of the old robots.txt that the new one does not have.
+	RemovedSitemaps []string
+}
+
+// AgentChanges is what changed for one robot (user-agent).
+type AgentChanges struct {
+	// Agent is the lower cased name of the robot, i.e. "googlebot".
+	Agent string
+
+	// Blocked are the paths the robot could crawl before and can not crawl anymore.
+	Blocked []PathChange
+
+	// Allowed are the paths the robot could not crawl before and can crawl now.
+	Allowed []PathChange
+
+	// OldCrawlDelay and NewCrawlDelay are the crawl delays of the robot, they are the same when the crawl delay did not change.
+	OldCrawlDelay time.Duration
+	NewCrawlDelay time.Duration
+}
+
+/*
+PathChange is a set of paths that changed between allowed and blocked because of the same directives. The set is every path that the
+old directive decided and the new directive decides, which is usually all the paths that start with the new or old path:
+
+	PathChange{OldRule: nil, NewRule: &Rule{Path: "/cms/"}, Example: "/cms/"}
+	// Every path that starts with "/cms/" is blocked now and was allowed because no directive matched it.
+*/
+type PathChange struct {
+	// OldRule is the directive that decided the paths in the old robots.txt, nil when no directive matched them.
+	OldRule *Rule
+
+	// NewRule is the directive that decides the paths in the new robots.txt, nil when no directive matches them.
+	NewRule *Rule
+
+	// Example is the shortest path of the set.
+	Example string
+}
+
+// MoreRestrictive reports whether any robot can crawl less than before, which is a path that became blocked or a longer crawl delay.
+func (report Report) MoreRestrictive() bool {
+	for _, agent := range report.Agents {
+		if len(agent.Blocked) > 0 || agent.NewCrawlDelay > agent.OldCrawlDelay {
+			return true
+		}
+	}
+	return false
+}
+
+// Changed reports whether anything changed for crawlers.
+func (report Report) Changed() bool {
+	return len(report.Agents) > 0 || len(report.AddedSitemaps) > 0 || len(report.RemovedSitemaps) > 0
+}
+
+/*
+Diff reports what changed for crawlers between two versions of a robots.txt. Every path a robot can ask for is compared, not a sample
+of them, so a change is reported even when it only affects paths nobody thought of testing:
+
+	old: User-agent: *          new: User-agent: *
+	     Disallow: /cms/             Disallow: /cms/
+	                                 Disallow: /*?print
+
+	report, err := robotstxt.Diff(old, new)
+	// report.Agents[0].Blocked[0] is PathChange{NewRule: &Rule{Path: "/*?print"}, Example: "/?print"}
+
+Directives with many "*" in them can have more ways to match a path than there is time to compare, Diff then returns
+ErrTooComplex.
+*/
+func Diff(old, new *RobotsTxt) (Report, error) {
+	report := Report{}
+	for _, agent := range diffAgents(old, new) {
+		var err error
+		changes := AgentChanges{Agent: agent, OldCrawlDelay: old.CrawlDelay(agent), NewCrawlDelay: new.CrawlDelay(agent)}
+		changes.Blocked, changes.Allowed, err = diffRules(old.agentRules(agent), new.agentRules(agent))
+		if err != nil {
+			return Report{}, err
+		}
+		if len(changes.Blocked) > 0 || len(changes.Allowed) > 0 || changes.OldCrawlDelay != changes.NewCrawlDelay {
+			report.Agents = append(report.Agents, changes)
+		}
+	}
+
+	report.AddedSitemaps = missingStrings(new.sitemaps, old.sitemaps)
+	report.RemovedSitemaps = missingStrings(old.sitemaps, new.sitemaps)
+	return report, nil
+}
+
+// diffRules compares the directives of two groups, it returns one PathChange for every pair of directives that decide paths
+// differently.
+func diffRules(old, new []Rule) ([]PathChange, []PathChange, error) {
+	var blocked, allowed []PathChange
+	rules := append(append([]Rule{}, old...), new...)
+	automaton := newAutomaton(rules)
+	seen := make(map[[2]int]bool)
+	err := automaton.explore(automaton.start(), "/", func(state *automatonState, path string) bool {
+		// The directives of the old group come first, so the matches of each group are next to each other.
+		matches := automaton.matches(state)
+		split := sort.SearchInts(matches, len(old))
+		oldAllowed, oldRule := decide(rules, matches[:split])
+		newAllowed, newRule := decide(rules, matches[split:])
+		if oldAllowed == newAllowed || seen[[2]int{oldRule, newRule}] {
+			return true
+		}
+		seen[[2]int{oldRule, newRule}] = true
+
+		change := PathChange{OldRule: ruleAt(rules, oldRule), NewRule: ruleAt(rules, newRule), Example: path}
+		if newAllowed {
+			allowed = append(allowed, change)
+		} else {
+			blocked = append(blocked, change)
+		}
+		return true
+	})
+	return blocked, allowed, err
+}
+
+// agentRules returns the directives that apply to a robot, disallow directives first the same way the index is built.
+func (robotsTxt *RobotsTxt) agentRules(robotName string) []Rule {
+	robot, _ := findMatchingRobot(robotName, robotsTxt.robots)
+	rules := make([]Rule, 0, len(robot.disallowed)+len(robot.allowed))
+	for _, path := range robot.disallowed {
+		rules = append(rules, Rule{Path: path})
+	}
+	for _, path := range robot.allowed {
+		rules = append(rules, Rule{Allow: true, Path: path})
+	}
+	return rules
+}
+
+// diffAgents returns the lower cased robots that are named in either robots.txt and "*" for the rest of them, sorted by name.
+func diffAgents(robotsTxts ...*RobotsTxt) []string {
+	unique := map[string]bool{"*": true}
+	for _, robotsTxt := range robotsTxts {
+		for name := range robotsTxt.robots {
+			if name != "" {
+				unique[strings.ToLower(name)] = true
+			}
+		}
+	}
+	agents := make([]string, 0, len(unique))
+	for agent := range unique {
+		agents = append(agents, agent)
+	}
+	sort.Strings(agents)
+	return agents
+}
+
+func ruleAt(rules []Rule, i int) *Rule {
+	if i == -1 {
+		return nil
+	}
+	rule := rules[i]
+	return &rule
+}
+
+// missingStrings returns the strings of a that are not in b.
+func missingStrings(a, b []string) []string {
+	var missing []string
+	found := make(map[string]bool, len(b))
+	for _, s := range b {
+		found[s] = true
+	}
+	for _, s := range a {
+		if !found[s] {
+			found[s] = true
+			missing = append(missing, s)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,221 @@
+package robotstxt_test
+
+import (
+	"fmt"
+	"github.com/itmayziii/robotstxt/v2"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	old := newRobotsTxt(t, `User-agent: *
+Crawl-delay: 5
+Disallow: /cms/
+Allow: /cms/public/
+Disallow: /checkout
+
+User-agent: googlebot
+Disallow: /private/
+
+Sitemap: https://www.dumpsters.com/sitemap.xml
+Sitemap: https://www.dumpsters.com/sitemap-old.xml
+`)
+	new := newRobotsTxt(t, `User-agent: *
+Crawl-delay: 10
+Disallow: /cms/
+Disallow: /*.pdf$
+Disallow: /checkout
+
+User-agent: bingbot
+Disallow:
+
+Sitemap: https://www.dumpsters.com/sitemap.xml
+Sitemap: https://www.dumpsters.com/sitemap-new.xml
+`)
+
+	report := diff(t, old, new)
+	assert.True(t, report.Changed())
+	assert.True(t, report.MoreRestrictive())
+	assert.Equal(t, []string{"https://www.dumpsters.com/sitemap-new.xml"}, report.AddedSitemaps)
+	assert.Equal(t, []string{"https://www.dumpsters.com/sitemap-old.xml"}, report.RemovedSitemaps)
+	assert.Equal(t, []robotstxt.AgentChanges{
+		{
+			Agent: "*",
+			Blocked: []robotstxt.PathChange{
+				{OldRule: nil, NewRule: &robotstxt.Rule{Path: "/*.pdf$"}, Example: "/.pdf"},
+				{OldRule: &robotstxt.Rule{Allow: true, Path: "/cms/public/"}, NewRule: &robotstxt.Rule{Path: "/cms/"}, Example: "/cms/public/"},
+				{OldRule: &robotstxt.Rule{Allow: true, Path: "/cms/public/"}, NewRule: &robotstxt.Rule{Path: "/*.pdf$"}, Example: "/cms/public/.pdf"},
+			},
+			OldCrawlDelay: 5 * time.Second,
+			NewCrawlDelay: 10 * time.Second,
+		},
+		{
+			// Bingbot followed the group of "*" and has a group of its own now.
+			Agent: "bingbot",
+			Allowed: []robotstxt.PathChange{
+				{OldRule: &robotstxt.Rule{Path: "/cms/"}, NewRule: nil, Example: "/cms/"},
+				{OldRule: &robotstxt.Rule{Path: "/checkout"}, NewRule: nil, Example: "/checkout"},
+			},
+			OldCrawlDelay: 5 * time.Second,
+		},
+		{
+			// Googlebot follows the group of "*" now.
+			Agent: "googlebot",
+			Blocked: []robotstxt.PathChange{
+				{OldRule: nil, NewRule: &robotstxt.Rule{Path: "/cms/"}, Example: "/cms/"},
+				{OldRule: nil, NewRule: &robotstxt.Rule{Path: "/*.pdf$"}, Example: "/.pdf"},
+				{OldRule: nil, NewRule: &robotstxt.Rule{Path: "/checkout"}, Example: "/checkout"},
+			},
+			Allowed: []robotstxt.PathChange{
+				{OldRule: &robotstxt.Rule{Path: "/private/"}, NewRule: nil, Example: "/private/"},
+			},
+			NewCrawlDelay: 10 * time.Second,
+		},
+	}, report.Agents)
+
+	report = diff(t, new, old)
+	assert.True(t, report.MoreRestrictive())
+	report = diff(t, old, old)
+	assert.False(t, report.Changed())
+	assert.False(t, report.MoreRestrictive())
+}
+
+func TestDiff_less_restrictive(t *testing.T) {
+	old := newRobotsTxt(t, "User-agent: *\nDisallow: /cms/\n")
+	new := newRobotsTxt(t, "User-agent: *\nDisallow: /cms/\nAllow: /cms/public/\n")
+	report := diff(t, old, new)
+	assert.True(t, report.Changed())
+	assert.False(t, report.MoreRestrictive())
+
+	// The same rules in another order or with a redundant rule make no difference.
+	new = newRobotsTxt(t, "User-agent: *\nDisallow: /cms/pages/\nDisallow: /cms/\n")
+	assert.False(t, diff(t, old, new).Changed())
+}
+
+func TestDiff_finds_what_sampling_finds(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	rules := []string{
+		"Disallow: /", "Allow: /", "Disallow: /cms/", "Allow: /cms/", "Allow: /cms/public/", "Disallow: /*.pdf$", "Allow: /*?print",
+		"Disallow: /*/drafts/", "Allow: /cms/public/*.html$", "Disallow: /pricing", "Allow: /pricing/", "Disallow: *?s=",
+	}
+	source := func() string {
+		lines := []string{"User-agent: *"}
+		for i := random.Intn(6); i >= 0; i-- {
+			lines = append(lines, rules[random.Intn(len(rules))])
+		}
+		return strings.Join(lines, "\n")
+	}
+	paths := []string{
+		"/", "/cms", "/cms/", "/cms/public/", "/cms/public/index.html", "/cms/public/brochure.pdf", "/cms/drafts/", "/pricing",
+		"/pricing/", "/pricing?s=1", "/pricing/?print", "/blog/drafts/post", "/brochure.pdf", "/brochure.pdf?print",
+	}
+
+	for round := 0; round < 200; round++ {
+		old, new := newRobotsTxt(t, source()), newRobotsTxt(t, source())
+		report := diff(t, old, new)
+		var blocked, allowed []robotstxt.PathChange
+		if len(report.Agents) > 0 {
+			blocked, allowed = report.Agents[0].Blocked, report.Agents[0].Allowed
+		}
+
+		// Every sampled change is reported and every example really changed.
+		for _, path := range paths {
+			before, _ := old.CanCrawl("googlebot", path)
+			after, _ := new.CanCrawl("googlebot", path)
+			if before && !after {
+				assert.NotEmpty(t, blocked, path)
+			}
+			if !before && after {
+				assert.NotEmpty(t, allowed, path)
+			}
+		}
+		for _, change := range blocked {
+			assert.Equal(t, []bool{true}, old.CanCrawlBatch("googlebot", []string{change.Example}), change.Example)
+			assert.Equal(t, []bool{false}, new.CanCrawlBatch("googlebot", []string{change.Example}), change.Example)
+		}
+		for _, change := range allowed {
+			assert.Equal(t, []bool{false}, old.CanCrawlBatch("googlebot", []string{change.Example}), change.Example)
+			assert.Equal(t, []bool{true}, new.CanCrawlBatch("googlebot", []string{change.Example}), change.Example)
+		}
+	}
+}
+
+func newRobotsTxt(t *testing.T, source string) *robotstxt.RobotsTxt {
+	robotsTxt, err := robotstxt.New("https://www.dumpsters.com", strings.NewReader(source))
+	assert.Nil(t, err)
+	return robotsTxt
+}
+
+func diff(t *testing.T, old, new *robotstxt.RobotsTxt) robotstxt.Report {
+	report, err := robotstxt.Diff(old, new)
+	assert.Nil(t, err)
+	return report
+}
+
+func ExampleDiff() {
+	old, _ := robotstxt.New("https://www.dumpsters.com", strings.NewReader("User-agent: *\nDisallow: /cms/\n"))
+	new, _ := robotstxt.New("https://www.dumpsters.com", strings.NewReader("User-agent: *\nDisallow: /cms/\nDisallow: /*/drafts/\n"))
+	report, _ := robotstxt.Diff(old, new)
+	for _, change := range report.Agents[0].Blocked {
+		fmt.Printf("%s blocked by %q, for example %s\n", report.Agents[0].Agent, change.NewRule, change.Example)
+	}
+	fmt.Println(report.MoreRestrictive())
+	// Output:
+	// * blocked by "Disallow: /*/drafts/", for example /a/drafts/
+	// true
+}
+
+// Diff has to stay fast for the large files that sites pile up, the time it takes grows with the directives and not with the paths.
+func TestDiff_large_files(t *testing.T) {
+	old, err := robotstxt.New("https://www.dumpsters.com", manyRulesRobotsTxt(2000))
+	assert.Nil(t, err)
+	changes := strings.NewReader("Disallow: /*.pdf$\nAllow: /products/category-2/\n")
+	new, err := robotstxt.New("https://www.dumpsters.com", io.MultiReader(manyRulesRobotsTxt(2000), changes))
+	assert.Nil(t, err)
+
+	start := time.Now()
+	report := diff(t, old, new)
+	assert.True(t, time.Since(start) < 5*time.Second, time.Since(start).String())
+	assert.Len(t, report.Agents, 1)
+	assert.NotEmpty(t, report.Agents[0].Blocked)
+	assert.NotEmpty(t, report.Agents[0].Allowed)
+}
+
+// Directives with many stars have more ways to match a path than there is time to compare, Diff gives up instead of hanging.
+func TestDiff_too_complex(t *testing.T) {
+	old := newRobotsTxt(t, "User-agent: *\nDisallow: /cms/\n")
+	new := newRobotsTxt(t, hostileRobotsTxt(10))
+
+	start := time.Now()
+	_, err := robotstxt.Diff(old, new)
+	assert.Equal(t, robotstxt.ErrTooComplex, err)
+	assert.True(t, time.Since(start) < 5*time.Second, time.Since(start).String())
+}
+
+// hostileRobotsTxt has directives like "Disallow: /*a*d*h", every one of them multiplies the ways a path can be matched.
+func hostileRobotsTxt(rules int) string {
+	source := "User-agent: *\n"
+	for i := 0; i < rules; i++ {
+		source += fmt.Sprintf("Disallow: /*%c*%c*%c\n", 'a'+i, 'a'+(i+3)%26, 'a'+(i+7)%26)
+	}
+	return source
+}
+
+func BenchmarkDiff(b *testing.B) {
+	for _, rules := range []int{100, 2000} {
+		b.Run(fmt.Sprintf("%d_rules", rules), func(b *testing.B) {
+			old, _ := robotstxt.New("https://www.dumpsters.com", manyRulesRobotsTxt(rules))
+			changes := strings.NewReader("Disallow: /*.pdf$\n")
+			new, _ := robotstxt.New("https://www.dumpsters.com", io.MultiReader(manyRulesRobotsTxt(rules), changes))
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				_, _ = robotstxt.Diff(old, new)
+			}
+		})
+	}
+}
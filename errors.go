@@ -0,0 +1,88 @@
+package robotstxt
+
+import (
+	"errors"
+	"strconv"
+)
+
+// ErrOriginMismatch is returned when an absolute URL is checked against the robots.txt of a different scheme, host, or port.
+var ErrOriginMismatch = errors.New("absolute URL provided but the robot URL did not match")
+
+// ErrInvalidURL is returned when a URL that has to be absolute is missing its scheme or host.
+var ErrInvalidURL = errors.New("invalid URL provided for robot, the URL must have a valid schema and host")
+
+// ErrTooComplex is returned when the allow and disallow directives of a group have too many ways to match a path to compare every
+// path, which takes directives with many "*" in them like "Disallow: /*a*d*h".
+var ErrTooComplex = errors.New("the directives are too complex to check every path")
+
+// ErrGroupNotInFile is returned when a FileGroup is edited that is not a group of its File, i.e. the zero value of FileGroup.
+var ErrGroupNotInFile = errors.New("the group is not part of the file")
+
+// EncodingError is returned when a robots.txt is not UTF-8 encoded.
+type EncodingError struct {
+	// Line is the first line, counting from 1, with invalid UTF-8 in it.
+	Line int
+}
+
+func (encodingError *EncodingError) Error() string {
+	return "invalid encoding detected on line " + strconv.Itoa(encodingError.Line) + ", all characters must be UTF-8 encoded"
+}
+
+/*
+PatternError is returned by a Builder and FileGroup.AddRule when the path of an allow or disallow directive can not be written to a
+robots.txt. Every path of a robots.txt that is parsed can be matched, "*" and a "$" at the end are the only bytes with a meaning and
+everything else only matches itself:
+
+	builder.AddGroup("*").Disallow("cms/")
+	_, err := builder.Build("https://www.dumpsters.com")
+	var patternError *robotstxt.PatternError
+	if errors.As(err, &patternError) {
+		log.Println("fix", patternError.Pattern)
+	}
+*/
+type PatternError struct {
+	// Pattern is the path of the directive, i.e. "cms/".
+	Pattern string
+
+	// Err is why the pattern is invalid.
+	Err error
+}
+
+func (patternError *PatternError) Error() string {
+	message := "invalid pattern " + strconv.Quote(patternError.Pattern)
+	if patternError.Err != nil {
+		message += ", " + patternError.Err.Error()
+	}
+	return message
+}
+
+// Unwrap returns the reason the pattern is invalid.
+func (patternError *PatternError) Unwrap() error {
+	return patternError.Err
+}
+
+// FetchError is returned when a robots.txt could not be retrieved, either because the request failed, the server responded with a
+// status code that says nothing about what may be crawled, or the body could not be parsed.
+type FetchError struct {
+	// URL is the URL of the robots.txt, i.e. https://www.dumpsters.com/robots.txt.
+	URL string
+
+	// Status is the status code of the response, it is 0 when there was no response.
+	Status int
+
+	// Err is what went wrong, i.e. the error of the request or the reason the body could not be parsed. It is nil when the status
+	// code itself is the problem.
+	Err error
+}
+
+func (fetchError *FetchError) Error() string {
+	if fetchError.Err != nil {
+		return "unable to fetch " + fetchError.URL + ", " + fetchError.Err.Error()
+	}
+	return "unable to fetch " + fetchError.URL + ", unexpected status code " + strconv.Itoa(fetchError.Status)
+}
+
+// Unwrap returns what went wrong so errors.Is(err, context.DeadlineExceeded) and the like work.
+func (fetchError *FetchError) Unwrap() error {
+	return fetchError.Err
+}
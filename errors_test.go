@@ -0,0 +1,83 @@
+package robotstxt_test
+
+import (
+	"context"
+	"errors"
+	"github.com/itmayziii/robotstxt/v2"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestErrOriginMismatch(t *testing.T) {
+	robotsTxt, err := robotstxt.New("https://www.dumpsters.com", getExampleRobotsTxt())
+	assert.Nil(t, err)
+
+	_, err = robotsTxt.CanCrawl("googlebot", "http://www.dumpsters.com/cms/")
+	assert.True(t, errors.Is(err, robotstxt.ErrOriginMismatch))
+}
+
+func TestErrInvalidURL(t *testing.T) {
+	_, err := robotstxt.New("www.dumpsters.com", getExampleRobotsTxt())
+	assert.True(t, errors.Is(err, robotstxt.ErrInvalidURL))
+	assert.Contains(t, err.Error(), "www.dumpsters.com")
+}
+
+func TestEncodingError(t *testing.T) {
+	_, err := robotstxt.New("https://www.dumpsters.com", strings.NewReader("User-agent: *\nDisallow: /\xff/\n"))
+
+	var encodingError *robotstxt.EncodingError
+	assert.True(t, errors.As(err, &encodingError))
+	assert.Equal(t, 2, encodingError.Line)
+}
+
+func TestPatternError(t *testing.T) {
+	builder := &robotstxt.Builder{}
+	builder.AddGroup("*").Disallow("/cms/#top")
+	_, err := builder.Build("https://www.dumpsters.com")
+
+	var patternError *robotstxt.PatternError
+	assert.True(t, errors.As(err, &patternError))
+	assert.Equal(t, "/cms/#top", patternError.Pattern)
+	assert.NotNil(t, patternError.Unwrap())
+}
+
+func TestFetchError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	_, err := (&robotstxt.Fetcher{}).Fetch(context.Background(), server.URL)
+	var fetchError *robotstxt.FetchError
+	assert.True(t, errors.As(err, &fetchError))
+	assert.Equal(t, http.StatusServiceUnavailable, fetchError.Status)
+	assert.Equal(t, server.URL+"/robots.txt", fetchError.URL)
+	assert.Nil(t, fetchError.Err)
+
+	// Errors of the request are wrapped.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = (&robotstxt.Fetcher{}).Fetch(ctx, server.URL)
+	assert.True(t, errors.As(err, &fetchError))
+	assert.Equal(t, 0, fetchError.Status)
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestFetchError_wraps_parse_errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("User-agent: *\nCrawl-delay: soon\n"))
+	}))
+	defer server.Close()
+
+	_, err := (&robotstxt.Fetcher{}).Fetch(context.Background(), server.URL)
+	var fetchError *robotstxt.FetchError
+	assert.True(t, errors.As(err, &fetchError))
+	assert.Equal(t, http.StatusOK, fetchError.Status)
+	var numError *strconv.NumError
+	assert.True(t, errors.As(err, &numError))
+	assert.Contains(t, err.Error(), "line 2")
+}
@@ -0,0 +1,291 @@
+package robotstxt
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"golang.org/x/net/publicsuffix"
+	"io"
+	"io/ioutil"
+	"net/http"
+	netUrl "net/url"
+	"strconv"
+	"strings"
+)
+
+// defaultMaxRedirects is the number of consecutive redirects a crawler should follow according to RFC 9309,
+// https://www.rfc-editor.org/rfc/rfc9309.html#section-2.3.1.2.
+const defaultMaxRedirects = 5
+
+// maxFetchSize is how much of a robots.txt is read, RFC 9309 lets crawlers ignore everything after the first 500 KiB,
+// https://www.rfc-editor.org/rfc/rfc9309.html#section-2.5.
+const maxFetchSize = 500 << 10
+
+/*
+Fetcher retrieves robots.txt files over HTTP while keeping track of any redirects that happened along the way. The zero value is
+ready to use and behaves like http.DefaultClient that follows up to 5 redirects.
+
+According to RFC 9309, https://www.rfc-editor.org/rfc/rfc9309.html#section-2.3.1.2, a robots.txt that was reached through
+redirects applies to the origin that was originally requested, not to the origin that ended up serving the file:
+
+	Requested:                           Served by:                                Rules apply to:
+	https://example.com/robots.txt    -> https://www.example.com/robots.txt     -> https://example.com:443
+	https://example.com/robots.txt    -> https://cdn.example-cdn.net/robots.txt -> https://example.com:443
+*/
+type Fetcher struct {
+	// Client is used to make the HTTP requests, http.DefaultClient is used when nil. The "CheckRedirect" function of the client is
+	// replaced for the duration of a fetch so the redirect chain can be validated.
+	Client *http.Client
+
+	// UserAgent is sent as the "User-Agent" header when it is not empty.
+	UserAgent string
+
+	// MaxRedirects is the maximum number of consecutive redirects to follow, 5 is used when zero.
+	MaxRedirects int
+
+	// RejectCrossSiteRedirects makes a fetch fail when a redirect leaves the site of the requested origin, i.e.
+	// https://example.com -> https://www.example.com is allowed but https://example.com -> https://example-cdn.net is not.
+	RejectCrossSiteRedirects bool
+}
+
+// FetchResult is everything that is known about a robots.txt after it has been fetched.
+type FetchResult struct {
+	// RobotsTxt holds the parsed rules which always apply to the originally requested origin.
+	RobotsTxt *RobotsTxt
+
+	// URL is the robots.txt URL that was originally requested, i.e. https://www.dumpsters.com/robots.txt.
+	URL string
+
+	// FinalURL is the URL that actually served the robots.txt after following every redirect.
+	FinalURL string
+
+	// Redirects is the full redirect chain in the order it was followed, it is empty when no redirects happened.
+	Redirects []Redirect
+
+	// StatusCode is the HTTP status code of the final response.
+	StatusCode int
+
+	// Header holds the headers of the final response.
+	Header http.Header
+
+	// Body is the raw body of the final response, at most the first 500 KiB of it.
+	Body []byte
+}
+
+// Redirect is a single hop in a redirect chain.
+type Redirect struct {
+	// From is the URL that responded with a redirect.
+	From string
+
+	// To is the URL that was redirected to.
+	To string
+
+	// StatusCode is the HTTP status code of the redirect response, i.e. 301.
+	StatusCode int
+}
+
+// CrossOrigin reports whether the robots.txt was served by a different origin than the one that was requested.
+func (fetchResult *FetchResult) CrossOrigin() bool {
+	requested, err := normalizeUrl(fetchResult.URL)
+	if err != nil {
+		return false
+	}
+	final, err := normalizeUrl(fetchResult.FinalURL)
+	if err != nil {
+		return false
+	}
+	return requested != final
+}
+
+/*
+Fetch retrieves the robots.txt for the scheme, host, and optional port of the given URL, everything that is not the top level is
+ignored the same way NewFromURL does.
+
+The response status is handled as described in RFC 9309, https://www.rfc-editor.org/rfc/rfc9309.html#section-2.3.1:
+
+	2xx -> The body is parsed.
+	4xx -> The robots.txt is unavailable and everything is allowed.
+	5xx -> The robots.txt is unreachable and an error is returned.
+
+Only the first 500 KiB of the body are read, the rest is ignored so a host can not exhaust the memory of the crawler.
+*/
+func (fetcher *Fetcher) Fetch(ctx context.Context, url string) (*FetchResult, error) {
+	return fetcher.fetch(ctx, url, nil)
+}
+
+/*
+Revalidate is the same as Fetch but makes a conditional request using the "ETag" and "Last-Modified" values of a previous response,
+either of them can be empty. When the robots.txt has not changed the result has a 304 status code and no RobotsTxt, the previously
+fetched RobotsTxt is still valid.
+*/
+func (fetcher *Fetcher) Revalidate(ctx context.Context, url, etag, lastModified string) (*FetchResult, error) {
+	header := http.Header{}
+	if etag != "" {
+		header.Set("If-None-Match", etag)
+	}
+	if lastModified != "" {
+		header.Set("If-Modified-Since", lastModified)
+	}
+	return fetcher.fetch(ctx, url, header)
+}
+
+// Get fetches the RobotsTxt for the origin of the URL, it makes a Fetcher usable as a Getter.
+func (fetcher *Fetcher) Get(ctx context.Context, url string) (*RobotsTxt, error) {
+	fetchResult, err := fetcher.Fetch(ctx, url)
+	if err != nil {
+		return &RobotsTxt{}, err
+	}
+	return fetchResult.RobotsTxt, nil
+}
+
+func (fetcher *Fetcher) fetch(ctx context.Context, url string, header http.Header) (*FetchResult, error) {
+	origin, err := normalizeUrl(url)
+	if err != nil {
+		return &FetchResult{}, err
+	}
+	robotsTxtUrl, err := robotsTxtURL(url)
+	if err != nil {
+		return &FetchResult{}, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, robotsTxtUrl, nil)
+	if err != nil {
+		return &FetchResult{}, err
+	}
+	req = req.WithContext(ctx)
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	if fetcher.UserAgent != "" {
+		req.Header.Set("User-Agent", fetcher.UserAgent)
+	}
+
+	client := fetcher.client()
+	resp, err := client.Do(req)
+	if err != nil {
+		return &FetchResult{URL: robotsTxtUrl}, &FetchError{URL: robotsTxtUrl, Err: err}
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxFetchSize))
+	if err != nil {
+		return &FetchResult{URL: robotsTxtUrl}, &FetchError{URL: robotsTxtUrl, Status: resp.StatusCode, Err: err}
+	}
+
+	fetchResult := &FetchResult{
+		URL:        robotsTxtUrl,
+		FinalURL:   resp.Request.URL.String(),
+		Redirects:  redirectChain(resp),
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       body,
+	}
+
+	if resp.StatusCode == http.StatusNotModified && len(header) > 0 {
+		// Nothing to parse, the caller already has the RobotsTxt.
+		return fetchResult, nil
+	}
+
+	fetchResult.RobotsTxt, err = parseResponse(origin, robotsTxtUrl, resp.StatusCode, body)
+	if err != nil {
+		return fetchResult, err
+	}
+
+	return fetchResult, nil
+}
+
+// parseResponse creates the RobotsTxt for an origin based on the status code and raw body of a response, every error is a *FetchError.
+func parseResponse(origin, robotsTxtUrl string, statusCode int, body []byte) (*RobotsTxt, error) {
+	switch {
+	case statusCode >= 200 && statusCode < 300:
+		robotsTxtBody, err := parseRobotsTxtBody(ioutil.NopCloser(bytes.NewReader(body)))
+		if err != nil {
+			return nil, &FetchError{URL: robotsTxtUrl, Status: statusCode, Err: err}
+		}
+		robotsTxt, err := parse(origin, strings.NewReader(robotsTxtBody))
+		if err != nil {
+			return nil, &FetchError{URL: robotsTxtUrl, Status: statusCode, Err: err}
+		}
+		return robotsTxt, nil
+	case statusCode >= 400 && statusCode < 500:
+		return parse(origin, strings.NewReader(""))
+	default:
+		return nil, &FetchError{URL: robotsTxtUrl, Status: statusCode}
+	}
+}
+
+// client returns a copy of the configured client that validates every redirect.
+func (fetcher *Fetcher) client() *http.Client {
+	client := http.DefaultClient
+	if fetcher.Client != nil {
+		client = fetcher.Client
+	}
+	clientCopy := *client
+
+	maxRedirects := fetcher.MaxRedirects
+	if maxRedirects <= 0 {
+		maxRedirects = defaultMaxRedirects
+	}
+	clientCopy.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) > maxRedirects {
+			return &redirectError{message: "stopped after " + strconv.Itoa(maxRedirects) + " redirects"}
+		}
+		if fetcher.RejectCrossSiteRedirects && !sameSite(via[0].URL, req.URL) {
+			return &redirectError{message: "cross-site redirect from " + via[0].URL.String() + " to " + req.URL.String() + " rejected"}
+		}
+		return nil
+	}
+	return &clientCopy
+}
+
+// redirectError is why a redirect was not followed, the site answered so it is not unreachable.
+type redirectError struct {
+	message string
+}
+
+func (redirectError *redirectError) Error() string {
+	return redirectError.message
+}
+
+// redirectChain walks backwards through the responses that lead up to the final response.
+func redirectChain(resp *http.Response) []Redirect {
+	var redirects []Redirect
+	for req := resp.Request; req != nil && req.Response != nil; req = req.Response.Request {
+		redirects = append([]Redirect{{
+			From:       req.Response.Request.URL.String(),
+			To:         req.URL.String(),
+			StatusCode: req.Response.StatusCode,
+		}}, redirects...)
+	}
+	return redirects
+}
+
+// sameSite compares the scheme and registrable domain, https://html.spec.whatwg.org/multipage/origin.html#same-site.
+func sameSite(a, b *netUrl.URL) bool {
+	if a.Scheme != b.Scheme {
+		return false
+	}
+	return registrableDomain(a.Hostname()) == registrableDomain(b.Hostname())
+}
+
+func registrableDomain(host string) string {
+	domain, err := publicsuffix.EffectiveTLDPlusOne(host)
+	if err != nil {
+		// IP addresses and public suffixes themselves do not have a registrable domain so the host has to match exactly.
+		return host
+	}
+	return domain
+}
+
+// robotsTxtURL returns the location of the robots.txt for the scheme, host, and optional port of a URL.
+func robotsTxtURL(url string) (string, error) {
+	parsedUrl, err := netUrl.Parse(url)
+	if err != nil {
+		return "", err
+	}
+	if parsedUrl.Scheme == "" || parsedUrl.Host == "" {
+		return "", fmt.Errorf("%w, url = %s", ErrInvalidURL, url)
+	}
+
+	return parsedUrl.Scheme + "://" + parsedUrl.Host + "/robots.txt", nil
+}
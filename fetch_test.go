@@ -0,0 +1,138 @@
+package robotstxt_test
+
+import (
+	"context"
+	"github.com/itmayziii/robotstxt/v2"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestFetcher_Fetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/robots.txt", r.URL.Path)
+		assert.Equal(t, "testbot", r.Header.Get("User-Agent"))
+		_, _ = w.Write([]byte("User-agent: *\nDisallow: /cms/\n"))
+	}))
+	defer server.Close()
+
+	fetcher := &robotstxt.Fetcher{UserAgent: "testbot"}
+	fetchResult, err := fetcher.Fetch(context.Background(), server.URL+"/pricing/roll-off-dumpsters")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, fetchResult.StatusCode)
+	assert.Equal(t, server.URL+"/robots.txt", fetchResult.URL)
+	assert.Equal(t, server.URL+"/robots.txt", fetchResult.FinalURL)
+	assert.Empty(t, fetchResult.Redirects)
+	assert.False(t, fetchResult.CrossOrigin())
+
+	canCrawl, err := fetchResult.RobotsTxt.CanCrawl("googlebot", "/cms/pages")
+	assert.Nil(t, err)
+	assert.False(t, canCrawl)
+}
+
+func TestFetcher_Fetch_reads_at_most_500_KiB(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("User-agent: *\nDisallow: /cms/\n"))
+		_, _ = w.Write([]byte(strings.Repeat("# padding\n", 100*1024)))
+		_, _ = w.Write([]byte("Disallow: /\n"))
+	}))
+	defer server.Close()
+
+	fetchResult, err := (&robotstxt.Fetcher{}).Fetch(context.Background(), server.URL)
+	assert.Nil(t, err)
+	assert.Len(t, fetchResult.Body, 500*1024)
+	canCrawl, err := fetchResult.RobotsTxt.CanCrawl("googlebot", "/products/")
+	assert.Nil(t, err)
+	assert.True(t, canCrawl)
+}
+
+func TestFetcher_Fetch_records_redirect_chain_and_applies_rules_to_requested_origin(t *testing.T) {
+	cdn := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("User-agent: *\nDisallow: /cms/\n"))
+	}))
+	defer cdn.Close()
+
+	www := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, cdn.URL+"/static/robots.txt", http.StatusFound)
+	}))
+	defer www.Close()
+
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, www.URL+"/robots.txt", http.StatusMovedPermanently)
+	}))
+	defer origin.Close()
+
+	fetchResult, err := (&robotstxt.Fetcher{}).Fetch(context.Background(), origin.URL)
+	assert.Nil(t, err)
+	assert.Equal(t, []robotstxt.Redirect{
+		{From: origin.URL + "/robots.txt", To: www.URL + "/robots.txt", StatusCode: http.StatusMovedPermanently},
+		{From: www.URL + "/robots.txt", To: cdn.URL + "/static/robots.txt", StatusCode: http.StatusFound},
+	}, fetchResult.Redirects)
+	assert.Equal(t, cdn.URL+"/static/robots.txt", fetchResult.FinalURL)
+	assert.True(t, fetchResult.CrossOrigin())
+
+	originRobotsTxt, err := robotstxt.New(origin.URL, strings.NewReader(""))
+	assert.Nil(t, err)
+	assert.Equal(t, originRobotsTxt.URL(), fetchResult.RobotsTxt.URL())
+
+	canCrawl, err := fetchResult.RobotsTxt.CanCrawl("googlebot", origin.URL+"/cms/pages")
+	assert.Nil(t, err)
+	assert.False(t, canCrawl)
+}
+
+func TestFetcher_Fetch_fails_after_too_many_redirects(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, server.URL+"/robots.txt", http.StatusFound)
+	}))
+	defer server.Close()
+
+	_, err := (&robotstxt.Fetcher{MaxRedirects: 2}).Fetch(context.Background(), server.URL)
+	assert.NotNil(t, err)
+}
+
+func TestFetcher_Fetch_rejects_cross_site_redirects(t *testing.T) {
+	cdn := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("User-agent: *\nDisallow: /\n"))
+	}))
+	defer cdn.Close()
+
+	// httptest servers listen on 127.0.0.1 so "localhost" is a different site.
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://localhost:"+strconv.Itoa(cdn.Listener.Addr().(*net.TCPAddr).Port)+"/robots.txt", http.StatusFound)
+	}))
+	defer origin.Close()
+
+	_, err := (&robotstxt.Fetcher{}).Fetch(context.Background(), origin.URL)
+	assert.Nil(t, err)
+
+	_, err = (&robotstxt.Fetcher{RejectCrossSiteRedirects: true}).Fetch(context.Background(), origin.URL)
+	assert.NotNil(t, err)
+}
+
+func TestFetcher_Fetch_status_codes(t *testing.T) {
+	statusCode := http.StatusNotFound
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(statusCode)
+		_, _ = w.Write([]byte("User-agent: *\nDisallow: /\n"))
+	}))
+	defer server.Close()
+
+	// Unavailable, everything is allowed.
+	fetchResult, err := (&robotstxt.Fetcher{}).Fetch(context.Background(), server.URL)
+	assert.Nil(t, err)
+	canCrawl, err := fetchResult.RobotsTxt.CanCrawl("googlebot", "/anything")
+	assert.Nil(t, err)
+	assert.True(t, canCrawl)
+
+	// Unreachable.
+	statusCode = http.StatusServiceUnavailable
+	fetchResult, err = (&robotstxt.Fetcher{}).Fetch(context.Background(), server.URL)
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, fetchResult.StatusCode)
+	assert.Nil(t, fetchResult.RobotsTxt)
+}
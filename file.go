@@ -0,0 +1,495 @@
+package robotstxt
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// LineKind is what a Line of a File is.
+type LineKind int
+
+const (
+	BlankLine LineKind = iota
+	CommentLine
+	UserAgentLine
+	AllowLine
+	DisallowLine
+	CrawlDelayLine
+	SitemapLine
+	// UnknownLine is a key value pair with a key this package does not understand, i.e. "Host: www.dumpsters.com".
+	UnknownLine
+	// InvalidLine is a line that is neither a comment nor a key value pair.
+	InvalidLine
+)
+
+var lineKinds = map[string]LineKind{
+	"user-agent":  UserAgentLine,
+	"allow":       AllowLine,
+	"disallow":    DisallowLine,
+	"crawl-delay": CrawlDelayLine,
+	"sitemap":     SitemapLine,
+}
+
+/*
+File is a robots.txt that keeps every byte it was parsed from, including comments, blank lines, spacing, line endings, and lines
+that are not valid. It is meant for tools that change a robots.txt written by hand:
+
+	file, err := robotstxt.ParseFile(strings.NewReader(`# Robots.txt for dumpsters.com
+	User-agent : Googlebot   # Google
+	Disallow:/cms/
+
+	Sitemap: https://www.dumpsters.com/sitemap.xml
+	`))
+	group := file.Group("googlebot")
+	if group == nil {
+		return errors.New("no group for googlebot")
+	}
+	err = group.AddRule(robotstxt.Rule{Path: "/tmp/"})
+	fmt.Print(file)
+
+prints
+
+	# Robots.txt for dumpsters.com
+	User-agent : Googlebot   # Google
+	Disallow:/cms/
+	Disallow: /tmp/
+
+	Sitemap: https://www.dumpsters.com/sitemap.xml
+
+Lines that are not edited are written exactly as they were read, new lines use the line ending of the file. Groups are found the same
+way New finds them so editing a group never changes the rules of another group. A File is not safe for concurrent use.
+*/
+type File struct {
+	lines []*Line
+}
+
+// Line is a single line of a File.
+type Line struct {
+	raw        string // The line without its line ending.
+	ending     string // "\n", "\r\n", or empty for the last line of a file that does not end in a line ending.
+	kind       LineKind
+	key        string
+	value      string
+	valueStart int // Position of the value in raw.
+	valueEnd   int
+	comment    string
+}
+
+// FileGroup is a group of a File, it stays valid while the File is edited. The zero value is not a group of any File, its edits
+// return ErrGroupNotInFile.
+type FileGroup struct {
+	file  *File
+	first *Line // The first user agent of the group.
+}
+
+// ParseFile reads a File, only an error of the reader can make it fail.
+func ParseFile(reader io.Reader) (*File, error) {
+	content, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return &File{}, fmt.Errorf("unable to read robots.txt: %w", err)
+	}
+
+	file := &File{}
+	text := string(content)
+	for text != "" {
+		end := strings.Index(text, "\n")
+		if end == -1 {
+			file.lines = append(file.lines, newLine(text, ""))
+			break
+		}
+		raw, ending := text[:end], "\n"
+		if strings.HasSuffix(raw, "\r") {
+			raw, ending = raw[:len(raw)-1], "\r\n"
+		}
+		file.lines = append(file.lines, newLine(raw, ending))
+		text = text[end+1:]
+	}
+	return file, nil
+}
+
+// newLine reads a line the same way parse does.
+func newLine(raw, ending string) *Line {
+	line := &Line{raw: raw, ending: ending}
+	content := raw
+	if position := strings.Index(raw, "#"); position != -1 {
+		content = raw[:position]
+		line.comment = raw[position:]
+	}
+
+	if strings.TrimSpace(raw) == "" {
+		line.kind = BlankLine
+		return line
+	}
+	if strings.TrimSpace(content) == "" {
+		line.kind = CommentLine
+		return line
+	}
+	separator := strings.Index(content, ":")
+	if separator == -1 || strings.TrimSpace(content[:separator]) == "" {
+		line.kind = InvalidLine
+		return line
+	}
+
+	line.key = strings.ToLower(strings.TrimSpace(content[:separator]))
+	line.kind = UnknownLine
+	if kind, exists := lineKinds[line.key]; exists {
+		line.kind = kind
+	}
+	// Only the first word of a value is used.
+	value := content[separator+1:]
+	trimmed := strings.TrimLeftFunc(value, unicode.IsSpace)
+	line.value = strings.Split(strings.TrimSpace(trimmed), " ")[0]
+	line.valueStart = separator + 1 + len(value) - len(trimmed)
+	line.valueEnd = line.valueStart + len(line.value)
+	return line
+}
+
+// Kind returns what the line is.
+func (line *Line) Kind() LineKind {
+	return line.kind
+}
+
+// Key returns the lower cased key of a key value pair.
+func (line *Line) Key() string {
+	return line.key
+}
+
+// Value returns the value of a key value pair the way New reads it, which is only the first word.
+func (line *Line) Value() string {
+	return line.value
+}
+
+// Comment returns the comment on the line including the "#".
+func (line *Line) Comment() string {
+	return line.comment
+}
+
+// String returns the line exactly as it is written without its line ending.
+func (line *Line) String() string {
+	return line.raw
+}
+
+// endsUserAgents reports whether the line ends the user agents of a group, a user agent after it starts a new group.
+func (line *Line) endsUserAgents() bool {
+	switch line.kind {
+	case AllowLine, DisallowLine:
+		return true
+	case CrawlDelayLine, SitemapLine:
+		return line.value != ""
+	}
+	return false
+}
+
+// directive reports whether the line is more than a comment or a blank line.
+func (line *Line) directive() bool {
+	return line.kind != BlankLine && line.kind != CommentLine
+}
+
+// indentation returns the white space the line starts with.
+func (line *Line) indentation() string {
+	return line.raw[:len(line.raw)-len(strings.TrimLeftFunc(line.raw, unicode.IsSpace))]
+}
+
+// Lines returns every line of the File.
+func (file *File) Lines() []*Line {
+	return append([]*Line(nil), file.lines...)
+}
+
+// Groups returns every group of the File in the order they are defined.
+func (file *File) Groups() []*FileGroup {
+	var groups []*FileGroup
+	for _, start := range file.groupStarts() {
+		groups = append(groups, &FileGroup{file: file, first: file.lines[start]})
+	}
+	return groups
+}
+
+// Group returns the last group that names a user agent, which is the group New uses for it. It returns nil when no group names the
+// user agent, the robot then follows the "*" group, if any.
+func (file *File) Group(userAgent string) *FileGroup {
+	groups := file.Groups()
+	for i := len(groups) - 1; i >= 0; i-- {
+		for _, agent := range groups[i].Agents() {
+			if strings.EqualFold(agent, userAgent) {
+				return groups[i]
+			}
+		}
+	}
+	return nil
+}
+
+/*
+AddGroup adds a group after the last group of the File, sitemaps, comments, and invalid lines at the end of the file stay at the
+end. The group is written like WriteTo writes it and separated from the rest of the File by blank lines.
+*/
+func (file *File) AddGroup(group Group) (*FileGroup, error) {
+	err := validateGroup(group)
+	if err != nil {
+		return nil, err
+	}
+
+	// The group that is last right now could not tell where it ends and the new group starts.
+	groups := file.Groups()
+	if len(groups) > 0 {
+		groups[len(groups)-1].endUserAgents()
+	}
+
+	index := len(file.lines)
+	for i := len(file.lines) - 1; i >= 0; i-- {
+		line := file.lines[i]
+		if !line.directive() || line.kind == SitemapLine || line.kind == InvalidLine {
+			continue
+		}
+		if line.endsUserAgents() {
+			index = i + 1
+		}
+		break
+	}
+
+	var builder strings.Builder
+	bufferedWriter := bufio.NewWriter(&builder)
+	writeGroup(bufferedWriter, group)
+	_ = bufferedWriter.Flush()
+	raws := strings.Split(strings.TrimSuffix(builder.String(), "\n"), "\n")
+	if index > 0 && file.lines[index-1].kind != BlankLine {
+		raws = append([]string{""}, raws...)
+	}
+	if index < len(file.lines) && file.lines[index].kind != BlankLine {
+		raws = append(raws, "")
+	}
+
+	for _, line := range file.insert(index, raws...) {
+		if line.kind == UserAgentLine {
+			return &FileGroup{file: file, first: line}, nil
+		}
+	}
+	return nil, errors.New("unable to add group")
+}
+
+// RobotsTxt parses the File for a URL, see New.
+func (file *File) RobotsTxt(url string) (*RobotsTxt, error) {
+	return New(url, strings.NewReader(file.String()))
+}
+
+// WriteTo writes the File, lines that were not edited are written byte for byte as they were read.
+func (file *File) WriteTo(writer io.Writer) (int64, error) {
+	countingWriter := &countingWriter{writer: writer}
+	bufferedWriter := bufio.NewWriter(countingWriter)
+	for _, line := range file.lines {
+		_, _ = bufferedWriter.WriteString(line.raw)
+		_, _ = bufferedWriter.WriteString(line.ending)
+	}
+	err := bufferedWriter.Flush()
+	return countingWriter.written, err
+}
+
+// String returns the File as text, see WriteTo.
+func (file *File) String() string {
+	var builder strings.Builder
+	_, _ = file.WriteTo(&builder)
+	return builder.String()
+}
+
+// groupStarts returns the index of the first user agent of every group, groups are found exactly like parse finds them.
+func (file *File) groupStarts() []int {
+	var starts []int
+	endUserAgents := false
+	for i, line := range file.lines {
+		if line.kind == UserAgentLine && line.value != "" {
+			if endUserAgents || len(starts) == 0 {
+				starts = append(starts, i)
+				endUserAgents = false
+			}
+			continue
+		}
+		if line.endsUserAgents() {
+			endUserAgents = true
+		}
+	}
+	return starts
+}
+
+// insert adds new lines before the line at index and returns them.
+func (file *File) insert(index int, raws ...string) []*Line {
+	ending := file.lineEnding()
+	lines := make([]*Line, len(raws))
+	for i, raw := range raws {
+		lines[i] = newLine(raw, ending)
+	}
+	// A file that does not end in a line ending keeps not doing so.
+	if index == len(file.lines) && index > 0 && file.lines[index-1].ending == "" {
+		file.lines[index-1].ending = ending
+		lines[len(lines)-1].ending = ""
+	}
+
+	file.lines = append(file.lines[:index], append(lines, file.lines[index:]...)...)
+	return lines
+}
+
+func (file *File) remove(index int) {
+	file.lines = append(file.lines[:index], file.lines[index+1:]...)
+}
+
+// lineEnding returns the line ending the file uses.
+func (file *File) lineEnding() string {
+	for _, line := range file.lines {
+		if line.ending != "" {
+			return line.ending
+		}
+	}
+	return "\n"
+}
+
+// Agents returns the user agents of the group.
+func (group *FileGroup) Agents() []string {
+	start, end, err := group.bounds()
+	if err != nil {
+		return nil
+	}
+	var agents []string
+	for _, line := range group.file.lines[start:end] {
+		if line.kind == UserAgentLine && line.value != "" {
+			agents = append(agents, line.value)
+		}
+	}
+	return agents
+}
+
+// Lines returns every line from the first user agent of the group up to the first user agent of the next group.
+func (group *FileGroup) Lines() []*Line {
+	start, end, err := group.bounds()
+	if err != nil {
+		return nil
+	}
+	return append([]*Line(nil), group.file.lines[start:end]...)
+}
+
+// AddRule adds an allow or disallow directive after the last directive of the group.
+func (group *FileGroup) AddRule(rule Rule) error {
+	err := validateRule(rule)
+	if err != nil {
+		return err
+	}
+	start, end, err := group.bounds()
+	if err != nil {
+		return err
+	}
+
+	key := "Disallow"
+	if rule.Allow {
+		key = "Allow"
+	}
+	index := group.lastDirective(start, end)
+	group.file.insert(index+1, group.file.lines[index].indentation()+key+": "+rule.Path)
+	return nil
+}
+
+/*
+RemoveRule removes every allow or disallow directive of the group with the path of the rule and reports whether there was one.
+When the group would be left without anything that ends its user agents the first of them is replaced by an empty "Disallow:" so
+the group does not become part of the next one.
+*/
+func (group *FileGroup) RemoveRule(rule Rule) (bool, error) {
+	start, end, err := group.bounds()
+	if err != nil {
+		return false, err
+	}
+	var matches []int
+	ended := false
+	for i := start; i < end; i++ {
+		line := group.file.lines[i]
+		if (line.kind == AllowLine || line.kind == DisallowLine) && (line.kind == AllowLine) == rule.Allow && line.value == rule.Path {
+			matches = append(matches, i)
+		} else if line.endsUserAgents() {
+			ended = true
+		}
+	}
+	if len(matches) == 0 {
+		return false, nil
+	}
+
+	if !ended && end < len(group.file.lines) {
+		first := group.file.lines[matches[0]]
+		*first = *newLine(first.indentation()+"Disallow:", first.ending)
+		matches = matches[1:]
+	}
+	for i := len(matches) - 1; i >= 0; i-- {
+		group.file.remove(matches[i])
+	}
+	return true, nil
+}
+
+// RenameAgent replaces a user agent of the group, only the name itself is changed on the line.
+func (group *FileGroup) RenameAgent(from, to string) error {
+	if !validValue(to) {
+		return errors.New("invalid user agent " + strconv.Quote(to))
+	}
+
+	start, end, err := group.bounds()
+	if err != nil {
+		return err
+	}
+	renamed := false
+	for _, line := range group.file.lines[start:end] {
+		if line.kind != UserAgentLine || line.value == "" || !strings.EqualFold(line.value, from) {
+			continue
+		}
+		*line = *newLine(line.raw[:line.valueStart]+to+line.raw[line.valueEnd:], line.ending)
+		renamed = true
+	}
+	if !renamed {
+		return errors.New("user agent " + strconv.Quote(from) + " is not part of the group")
+	}
+	return nil
+}
+
+// bounds returns the lines of the group as the range [start, end) of the lines of the file, ErrGroupNotInFile when the group is not
+// one of them.
+func (group *FileGroup) bounds() (int, int, error) {
+	if group.file == nil {
+		return 0, 0, ErrGroupNotInFile
+	}
+	starts := group.file.groupStarts()
+	for i, start := range starts {
+		if group.file.lines[start] != group.first {
+			continue
+		}
+		if i == len(starts)-1 {
+			return start, len(group.file.lines), nil
+		}
+		return start, starts[i+1], nil
+	}
+	return 0, 0, ErrGroupNotInFile
+}
+
+// lastDirective returns the index of the last line of the group in [start, end) that is not a comment, blank line, or sitemap.
+func (group *FileGroup) lastDirective(start, end int) int {
+	index := start
+	for i := start; i < end; i++ {
+		if group.file.lines[i].directive() && group.file.lines[i].kind != SitemapLine {
+			index = i
+		}
+	}
+	return index
+}
+
+// endUserAgents adds an empty "Disallow:" to a group that has nothing to end its user agents with.
+func (group *FileGroup) endUserAgents() {
+	start, end, err := group.bounds()
+	if err != nil {
+		return
+	}
+	for _, line := range group.file.lines[start:end] {
+		if line.endsUserAgents() {
+			return
+		}
+	}
+	index := group.lastDirective(start, end)
+	group.file.insert(index+1, group.file.lines[index].indentation()+"Disallow:")
+}
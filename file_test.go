@@ -0,0 +1,287 @@
+package robotstxt_test
+
+import (
+	"fmt"
+	"github.com/itmayziii/robotstxt/v2"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestParseFile_round_trip(t *testing.T) {
+	example, err := ioutil.ReadAll(getExampleRobotsTxt())
+	assert.Nil(t, err)
+	sources := []string{
+		string(example),
+		"",
+		"\n\n",
+		"User-agent: *\r\nDisallow: /cms/\r\n",
+		"User-agent: *\nDisallow: /cms/",
+		"  User-agent :\t* # everyone \n\tDisallow:/cms/ /pricing/\nnot a directive\n: no key\nHost: www.dumpsters.com\n",
+		"User-agent: *\nDisallow: /\xff\n",
+	}
+	for _, source := range sources {
+		file, err := robotstxt.ParseFile(strings.NewReader(source))
+		assert.Nil(t, err)
+		assert.Equal(t, source, file.String())
+	}
+}
+
+func TestParseFile_lines(t *testing.T) {
+	file, err := robotstxt.ParseFile(strings.NewReader("# Robots.txt\n\n  User-agent :\tGooglebot news # Google\nDisallow:\nHost: www.dumpsters.com\nnope\n"))
+	assert.Nil(t, err)
+	lines := file.Lines()
+	assert.Len(t, lines, 6)
+
+	kinds := []robotstxt.LineKind{
+		robotstxt.CommentLine, robotstxt.BlankLine, robotstxt.UserAgentLine, robotstxt.DisallowLine, robotstxt.UnknownLine, robotstxt.InvalidLine,
+	}
+	for i, kind := range kinds {
+		assert.Equal(t, kind, lines[i].Kind(), lines[i].String())
+	}
+	assert.Equal(t, "user-agent", lines[2].Key())
+	assert.Equal(t, "Googlebot", lines[2].Value())
+	assert.Equal(t, "# Google", lines[2].Comment())
+	assert.Equal(t, "  User-agent :\tGooglebot news # Google", lines[2].String())
+	assert.Equal(t, "", lines[3].Value())
+	assert.Equal(t, "host", lines[4].Key())
+}
+
+func TestFileGroup_AddRule(t *testing.T) {
+	file, err := robotstxt.ParseFile(strings.NewReader(`# Robots.txt for dumpsters.com
+User-agent : Googlebot   # Google
+Disallow:/cms/
+  # Admin pages
+  Disallow:/pricing/admin/
+
+User-agent: *
+Disallow: /
+
+Sitemap: https://www.dumpsters.com/sitemap.xml`))
+	assert.Nil(t, err)
+
+	assert.Nil(t, file.Group("googlebot").AddRule(robotstxt.Rule{Path: "/tmp/"}))
+	assert.Nil(t, file.Group("*").AddRule(robotstxt.Rule{Allow: true, Path: "/products/"}))
+	assert.NotNil(t, file.Group("*").AddRule(robotstxt.Rule{Path: "/tmp/ # temporary"}))
+	assert.Equal(t, `# Robots.txt for dumpsters.com
+User-agent : Googlebot   # Google
+Disallow:/cms/
+  # Admin pages
+  Disallow:/pricing/admin/
+  Disallow: /tmp/
+
+User-agent: *
+Disallow: /
+Allow: /products/
+
+Sitemap: https://www.dumpsters.com/sitemap.xml`, file.String())
+
+	robotsTxt, err := file.RobotsTxt("https://www.dumpsters.com")
+	assert.Nil(t, err)
+	testRobot(t, "googlebot", robotsTxt, []testUrl{
+		{url: "/tmp/file", crawlable: false, hasError: false},
+		{url: "/products/", crawlable: true, hasError: false},
+	})
+	testRobot(t, "bingbot", robotsTxt, []testUrl{
+		{url: "/products/", crawlable: true, hasError: false},
+		{url: "/tmp/file", crawlable: false, hasError: false},
+	})
+}
+
+func TestFileGroup_AddRule_line_endings(t *testing.T) {
+	file, err := robotstxt.ParseFile(strings.NewReader("User-agent: *\r\nDisallow: /cms/"))
+	assert.Nil(t, err)
+	assert.Nil(t, file.Group("*").AddRule(robotstxt.Rule{Path: "/tmp/"}))
+	assert.Equal(t, "User-agent: *\r\nDisallow: /cms/\r\nDisallow: /tmp/", file.String())
+}
+
+func TestFileGroup_RemoveRule(t *testing.T) {
+	file, err := robotstxt.ParseFile(strings.NewReader(`User-agent: googlebot
+Disallow: /cms/ # CMS
+# Pricing
+Disallow: /pricing/
+Disallow: /cms/
+User-agent: bingbot
+Disallow: /
+`))
+	assert.Nil(t, err)
+	group := file.Group("googlebot")
+
+	removed, err := group.RemoveRule(robotstxt.Rule{Allow: true, Path: "/cms/"})
+	assert.Nil(t, err)
+	assert.False(t, removed)
+	removed, err = group.RemoveRule(robotstxt.Rule{Path: "/cms/"})
+	assert.Nil(t, err)
+	assert.True(t, removed)
+	assert.Equal(t, "User-agent: googlebot\n# Pricing\nDisallow: /pricing/\nUser-agent: bingbot\nDisallow: /\n", file.String())
+
+	// Without any rule left googlebot would become part of the group of bingbot.
+	removed, err = group.RemoveRule(robotstxt.Rule{Path: "/pricing/"})
+	assert.Nil(t, err)
+	assert.True(t, removed)
+	assert.Equal(t, "User-agent: googlebot\n# Pricing\nDisallow:\nUser-agent: bingbot\nDisallow: /\n", file.String())
+	assert.Len(t, file.Groups(), 2)
+
+	robotsTxt, err := file.RobotsTxt("https://www.dumpsters.com")
+	assert.Nil(t, err)
+	testRobot(t, "googlebot", robotsTxt, []testUrl{{url: "/pricing/", crawlable: true, hasError: false}})
+	testRobot(t, "bingbot", robotsTxt, []testUrl{{url: "/pricing/", crawlable: false, hasError: false}})
+}
+
+func TestFileGroup_RenameAgent(t *testing.T) {
+	file, err := robotstxt.ParseFile(strings.NewReader("User-agent :  Googlebot   # Google\nUser-agent: bingbot\nDisallow: /cms/\n"))
+	assert.Nil(t, err)
+	group := file.Groups()[0]
+
+	assert.Nil(t, group.RenameAgent("googlebot", "Googlebot-News"))
+	assert.NotNil(t, group.RenameAgent("googlebot", "Googlebot-Image"))
+	assert.NotNil(t, group.RenameAgent("bingbot", "bing bot"))
+	assert.Equal(t, "User-agent :  Googlebot-News   # Google\nUser-agent: bingbot\nDisallow: /cms/\n", file.String())
+	assert.Equal(t, []string{"Googlebot-News", "bingbot"}, group.Agents())
+	assert.Nil(t, file.Group("googlebot"))
+}
+
+// A FileGroup that is not a group of a File is never edited.
+func TestFileGroup_not_in_file(t *testing.T) {
+	group := &robotstxt.FileGroup{}
+	assert.Equal(t, robotstxt.ErrGroupNotInFile, group.AddRule(robotstxt.Rule{Path: "/tmp/"}))
+	removed, err := group.RemoveRule(robotstxt.Rule{Path: "/tmp/"})
+	assert.Equal(t, robotstxt.ErrGroupNotInFile, err)
+	assert.False(t, removed)
+	assert.Equal(t, robotstxt.ErrGroupNotInFile, group.RenameAgent("googlebot", "bingbot"))
+	assert.Nil(t, group.Agents())
+	assert.Nil(t, group.Lines())
+}
+
+func TestFile_AddGroup(t *testing.T) {
+	example, err := ioutil.ReadAll(getExampleRobotsTxt())
+	assert.Nil(t, err)
+	file, err := robotstxt.ParseFile(strings.NewReader(string(example)))
+	assert.Nil(t, err)
+
+	group, err := file.AddGroup(robotstxt.Group{Agents: []string{"dumpsterbot"}, Rules: []robotstxt.Rule{{Path: "/"}}, Comment: "Internal"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"dumpsterbot"}, group.Agents())
+	assert.Contains(t, file.String(), `User-agent: AdsBot-Bing
+Allow: /
+
+# Internal
+User-agent: dumpsterbot
+Disallow: /
+
+# Multiple sitemaps
+`)
+	assert.Equal(t, string(example), strings.Replace(file.String(), "# Internal\nUser-agent: dumpsterbot\nDisallow: /\n\n", "", 1))
+
+	// The last group has nothing that tells where it ends.
+	file, err = robotstxt.ParseFile(strings.NewReader("User-agent: googlebot"))
+	assert.Nil(t, err)
+	_, err = file.AddGroup(robotstxt.Group{Agents: []string{"bingbot"}, Rules: []robotstxt.Rule{{Path: "/"}}})
+	assert.Nil(t, err)
+	assert.Equal(t, "User-agent: googlebot\nDisallow:\n\nUser-agent: bingbot\nDisallow: /", file.String())
+
+	_, err = file.AddGroup(robotstxt.Group{})
+	assert.NotNil(t, err)
+}
+
+// Files are split into the same groups New splits them into and an edit only changes its own group.
+func TestFile_edits_random(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	lines := []string{
+		"User-agent: *", "user-agent : googlebot", "User-agent: bingbot", "User-agent:", "Disallow: /cms/", "Allow: /cms/public/",
+		"Disallow:", "Crawl-delay: 5", "Crawl-delay:", "Sitemap: https://www.dumpsters.com/sitemap.xml", "# Comment", "", "nope",
+		"Host: www.dumpsters.com",
+	}
+	for round := 0; round < 300; round++ {
+		var source []string
+		for i := random.Intn(12); i >= 0; i-- {
+			source = append(source, lines[random.Intn(len(lines))])
+		}
+		file, err := robotstxt.ParseFile(strings.NewReader(strings.Join(source, "\n")))
+		assert.Nil(t, err)
+		before := fileGroups(t, file)
+		assert.Equal(t, len(before), len(file.Groups()))
+		for i, group := range file.Groups() {
+			assert.Equal(t, before[i].Agents, group.Agents())
+		}
+		if len(before) == 0 {
+			continue
+		}
+
+		edited := random.Intn(len(before))
+		group := file.Groups()[edited]
+		rule := robotstxt.Rule{Path: "/tmp/"}
+		switch random.Intn(3) {
+		case 0:
+			assert.Nil(t, group.AddRule(rule))
+			before[edited].Rules = append(before[edited].Rules, rule)
+		case 1:
+			rule = robotstxt.Rule{Path: "/cms/"}
+			removed, err := group.RemoveRule(rule)
+			assert.Nil(t, err)
+			if removed {
+				var rules []robotstxt.Rule
+				for _, kept := range before[edited].Rules {
+					if kept != rule {
+						rules = append(rules, kept)
+					}
+				}
+				before[edited].Rules = rules
+			}
+		case 2:
+			_, err = file.AddGroup(robotstxt.Group{Agents: []string{"dumpsterbot"}, Rules: []robotstxt.Rule{rule}})
+			assert.Nil(t, err)
+			before = append(before, robotstxt.Group{Agents: []string{"dumpsterbot"}, Rules: []robotstxt.Rule{rule}})
+		}
+
+		after := fileGroups(t, file)
+		for i := range after {
+			// An empty "Disallow:" may be added to keep groups apart.
+			var rules []robotstxt.Rule
+			for _, rule := range after[i].Rules {
+				if rule.Path != "" {
+					rules = append(rules, rule)
+				}
+			}
+			after[i].Rules = rules
+		}
+		for i := range before {
+			var rules []robotstxt.Rule
+			for _, rule := range before[i].Rules {
+				if rule.Path != "" {
+					rules = append(rules, rule)
+				}
+			}
+			before[i].Rules = rules
+		}
+		assert.Equal(t, before, after, strings.Join(source, "\n")+"\n----\n"+file.String())
+	}
+}
+
+func fileGroups(t *testing.T, file *robotstxt.File) []robotstxt.Group {
+	robotsTxt, err := file.RobotsTxt("https://www.dumpsters.com")
+	assert.Nil(t, err)
+	return robotsTxt.Groups()
+}
+
+func ExampleFile() {
+	file, _ := robotstxt.ParseFile(strings.NewReader(`# Robots.txt for dumpsters.com
+User-agent : Googlebot   # Google
+Disallow:/cms/
+
+Sitemap: https://www.dumpsters.com/sitemap.xml
+`))
+	if group := file.Group("googlebot"); group != nil {
+		_ = group.AddRule(robotstxt.Rule{Path: "/tmp/"})
+	}
+	fmt.Print(file)
+	// Output:
+	// # Robots.txt for dumpsters.com
+	// User-agent : Googlebot   # Google
+	// Disallow:/cms/
+	// Disallow: /tmp/
+	//
+	// Sitemap: https://www.dumpsters.com/sitemap.xml
+}
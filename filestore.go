@@ -0,0 +1,195 @@
+package robotstxt
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	netUrl "net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const fileStoreExtension = ".robotstxt"
+
+/*
+FileStore is a Store that keeps one file per origin in a directory, the fetch metadata is written as a line of JSON followed by the
+raw robots.txt body as is:
+
+	https%3A%2F%2Fwww.dumpsters.com%3A443.robotstxt
+
+	{"origin":"https://www.dumpsters.com:443","statusCode":200,...}
+	User-agent: *
+	Disallow: /cms/
+
+Files are replaced atomically so a crash never leaves a half written entry, or a body with the metadata of another one, behind. A
+FileStore is safe for concurrent use within a process, multiple processes should not share a directory.
+*/
+type FileStore struct {
+	dir string
+	mu  sync.RWMutex
+}
+
+type fileStoreMetadata struct {
+	Origin       string     `json:"origin"`
+	URL          string     `json:"url,omitempty"`
+	FinalURL     string     `json:"finalUrl,omitempty"`
+	Redirects    []Redirect `json:"redirects,omitempty"`
+	StatusCode   int        `json:"statusCode"`
+	FetchedAt    time.Time  `json:"fetchedAt"`
+	Expires      time.Time  `json:"expires"`
+	ETag         string     `json:"etag,omitempty"`
+	LastModified string     `json:"lastModified,omitempty"`
+}
+
+// NewFileStore creates a FileStore that keeps its files in dir, the directory is created if it does not exist.
+func NewFileStore(dir string) (*FileStore, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return &FileStore{}, err
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// Get reads the entry for a normalized origin, the RobotsTxt of the returned entry is always nil.
+func (fileStore *FileStore) Get(origin string) (*CacheEntry, bool, error) {
+	fileStore.mu.RLock()
+	defer fileStore.mu.RUnlock()
+
+	data, err := ioutil.ReadFile(fileStore.path(origin))
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	end := bytes.IndexByte(data, '\n')
+	if end == -1 {
+		return nil, false, errors.New("invalid entry for " + strconv.Quote(origin) + ", it has no metadata line")
+	}
+	var metadata fileStoreMetadata
+	err = json.Unmarshal(data[:end], &metadata)
+	if err != nil {
+		return nil, false, err
+	}
+	body := data[end+1:]
+
+	return &CacheEntry{
+		Origin:       metadata.Origin,
+		URL:          metadata.URL,
+		FinalURL:     metadata.FinalURL,
+		Redirects:    metadata.Redirects,
+		StatusCode:   metadata.StatusCode,
+		Body:         body,
+		FetchedAt:    metadata.FetchedAt,
+		Expires:      metadata.Expires,
+		ETag:         metadata.ETag,
+		LastModified: metadata.LastModified,
+	}, true, nil
+}
+
+// Put writes the metadata and body of the entry to a single file.
+func (fileStore *FileStore) Put(cacheEntry *CacheEntry) error {
+	metadataBytes, err := json.Marshal(fileStoreMetadata{
+		Origin:       cacheEntry.Origin,
+		URL:          cacheEntry.URL,
+		FinalURL:     cacheEntry.FinalURL,
+		Redirects:    cacheEntry.Redirects,
+		StatusCode:   cacheEntry.StatusCode,
+		FetchedAt:    cacheEntry.FetchedAt,
+		Expires:      cacheEntry.Expires,
+		ETag:         cacheEntry.ETag,
+		LastModified: cacheEntry.LastModified,
+	})
+	if err != nil {
+		return err
+	}
+
+	data := make([]byte, 0, len(metadataBytes)+1+len(cacheEntry.Body))
+	data = append(append(append(data, metadataBytes...), '\n'), cacheEntry.Body...)
+
+	fileStore.mu.Lock()
+	defer fileStore.mu.Unlock()
+	return fileStore.writeFile(fileStore.path(cacheEntry.Origin), data)
+}
+
+// Delete removes the file for a normalized origin.
+func (fileStore *FileStore) Delete(origin string) error {
+	fileStore.mu.Lock()
+	defer fileStore.mu.Unlock()
+
+	err := os.Remove(fileStore.path(origin))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Iterate calls fn for every stored entry until fn returns false.
+func (fileStore *FileStore) Iterate(fn func(cacheEntry *CacheEntry) bool) error {
+	fileStore.mu.RLock()
+	fileInfos, err := ioutil.ReadDir(fileStore.dir)
+	fileStore.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	for _, fileInfo := range fileInfos {
+		name := fileInfo.Name()
+		if fileInfo.IsDir() || !strings.HasSuffix(name, fileStoreExtension) {
+			continue
+		}
+		origin, err := netUrl.QueryUnescape(strings.TrimSuffix(name, fileStoreExtension))
+		if err != nil {
+			continue
+		}
+
+		cacheEntry, exists, err := fileStore.Get(origin)
+		if err != nil {
+			return err
+		}
+		// Deleted after the directory was read.
+		if !exists {
+			continue
+		}
+		if !fn(cacheEntry) {
+			break
+		}
+	}
+	return nil
+}
+
+func (fileStore *FileStore) path(origin string) string {
+	return filepath.Join(fileStore.dir, netUrl.QueryEscape(origin)+fileStoreExtension)
+}
+
+// writeFile writes to a temporary file first and renames it so readers never see a partially written file.
+func (fileStore *FileStore) writeFile(path string, data []byte) error {
+	file, err := ioutil.TempFile(fileStore.dir, ".tmp-")
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(data)
+	if err == nil {
+		err = file.Sync()
+	}
+	closeErr := file.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(file.Name())
+		return err
+	}
+
+	err = os.Rename(file.Name(), path)
+	if err != nil {
+		_ = os.Remove(file.Name())
+	}
+	return err
+}
@@ -0,0 +1,65 @@
+package robotstxt
+
+import (
+	"context"
+	"sync"
+)
+
+// flightGroup makes concurrent calls for the same key share one call, the callers that come in while a call is in flight wait for
+// its result instead of making the call again. Cache and Registry use it so an origin is only retrieved once at a time. The zero
+// value is ready to use.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+type flightCall struct {
+	done  chan struct{}
+	value interface{}
+	err   error
+
+	// canceled is set when the call failed because the context of the caller that made it was done, the callers that waited for it
+	// make the call again with their own context.
+	canceled bool
+}
+
+// do calls fn for a key unless a call for the key is already in flight, in which case it waits for that call and returns its
+// result. fn must only use ctx, the context of the caller that makes the call.
+func (group *flightGroup) do(ctx context.Context, key string, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	for {
+		group.mu.Lock()
+		if group.calls == nil {
+			group.calls = make(map[string]*flightCall)
+		}
+		call, exists := group.calls[key]
+		if !exists {
+			break
+		}
+		group.mu.Unlock()
+
+		select {
+		case <-call.done:
+			if call.canceled && ctx.Err() == nil {
+				// The call was given up by the caller that made it, not by this one.
+				continue
+			}
+			return call.value, call.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	call := &flightCall{done: make(chan struct{})}
+	group.calls[key] = call
+	group.mu.Unlock()
+
+	call.value, call.err = fn(ctx)
+	call.canceled = call.err != nil && ctx.Err() != nil
+
+	group.mu.Lock()
+	delete(group.calls, key)
+	group.mu.Unlock()
+	close(call.done)
+
+	return call.value, call.err
+}
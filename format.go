@@ -0,0 +1,251 @@
+package robotstxt
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// canonicalKeys are the spellings Format uses for the directives this package understands.
+var canonicalKeys = map[string]string{
+	"user-agent":  "User-agent",
+	"allow":       "Allow",
+	"disallow":    "Disallow",
+	"crawl-delay": "Crawl-delay",
+	"sitemap":     "Sitemap",
+}
+
+// formatLine is a directive, or any other line that is not a comment, together with the comments that belong to it.
+type formatLine struct {
+	comments []string // Whole line comments above the line.
+	key      string   // Lower cased key, empty for lines without a key.
+	value    string   // Value without the key, empty for lines without a key.
+	text     string   // The line as it is written out, without its comment.
+	comment  string   // Comment at the end of the line.
+}
+
+type formatGroup struct {
+	agents []formatLine
+	delays []formatLine
+	rules  []formatLine // Allow, Disallow, and every other line of the group in the order they were defined.
+}
+
+/*
+Format rewrites a robots.txt in a canonical layout without changing what it allows and disallows:
+
+	# Robots.txt for dumpsters.com
+	User-agent : *
+	Disallow:/pricing/admin/
+	sitemap: https://www.dumpsters.com/sitemap.xml
+	disallow: /cms/   # SPA
+	Crawl-delay:5
+	Disallow:/pricing/admin/
+
+becomes
+
+	# Robots.txt for dumpsters.com
+	User-agent: *
+	Crawl-delay: 5
+	Disallow: /pricing/admin/
+	Disallow: /cms/ # SPA
+
+	Sitemap: https://www.dumpsters.com/sitemap.xml
+
+Keys are spelled the same way every time and separated from their value by ": ". The user agents of a group come first followed by
+its crawl delays and then its rules, groups are separated by a single blank line and sitemaps are moved to the end of the file.
+Rules, crawl delays, user agents, and sitemaps that are repeated are only written once. Comments stay with the line they are on or
+above, a comment that is separated from the rest of the file by a blank line at the top stays there. Directives that are not
+understood and lines that are not directives at all are kept where they are.
+*/
+func Format(reader io.Reader, writer io.Writer) error {
+	var header, pending []string
+	var preamble, sitemaps []formatLine
+	var groups []*formatGroup
+	endUserAgents := false
+	seenLine := false
+
+	lineScanner := bufio.NewScanner(reader)
+	for lineNumber := 1; lineScanner.Scan(); lineNumber++ {
+		rawLine := strings.TrimSpace(lineScanner.Text())
+		if validateUTF8(rawLine) == false {
+			return &EncodingError{Line: lineNumber}
+		}
+
+		if rawLine == "" {
+			// Comments at the top of the file that are followed by a blank line are about the whole file.
+			if !seenLine && len(pending) > 0 {
+				header = append(header, pending...)
+				pending = nil
+			}
+			continue
+		}
+		if strings.HasPrefix(rawLine, "#") {
+			pending = append(pending, rawLine)
+			continue
+		}
+
+		line := formatDirective(rawLine)
+		line.comments = pending
+		pending = nil
+		seenLine = true
+
+		key := line.key
+		// Only an allow or disallow without a value means something, see parse.
+		if line.value == "" && key != "allow" && key != "disallow" {
+			key = ""
+		}
+		switch key {
+		case "user-agent":
+			if endUserAgents || len(groups) == 0 {
+				groups = append(groups, &formatGroup{})
+				endUserAgents = false
+			}
+			group := groups[len(groups)-1]
+			group.agents = appendUnique(group.agents, line)
+		case "sitemap":
+			sitemaps = appendUnique(sitemaps, line)
+			endUserAgents = true
+		case "crawl-delay":
+			endUserAgents = true
+			if len(groups) == 0 {
+				preamble = append(preamble, line)
+				break
+			}
+			group := groups[len(groups)-1]
+			group.delays = appendLast(group.delays, line)
+		default:
+			if line.key == "allow" || line.key == "disallow" {
+				endUserAgents = true
+			}
+			if len(groups) == 0 {
+				preamble = append(preamble, line)
+				break
+			}
+			group := groups[len(groups)-1]
+			group.rules = appendUnique(group.rules, line)
+		}
+	}
+	if err := lineScanner.Err(); err != nil {
+		return err
+	}
+
+	bufferedWriter := bufio.NewWriter(writer)
+	sections := 0
+	section := func(lines []string) {
+		if len(lines) == 0 {
+			return
+		}
+		if sections > 0 {
+			_, _ = bufferedWriter.WriteString("\n")
+		}
+		for _, line := range lines {
+			_, _ = bufferedWriter.WriteString(line + "\n")
+		}
+		sections++
+	}
+
+	section(header)
+	section(formatLines(preamble))
+	for i, group := range groups {
+		lines := formatLines(group.agents)
+		lines = append(lines, formatLines(group.delays)...)
+		lines = append(lines, formatLines(group.rules)...)
+		// The sitemap that ended this group is moved to the end of the file, without a rule the next group would become part of it.
+		if i < len(groups)-1 && len(group.delays) == 0 && !hasRule(group.rules) {
+			lines = append(lines, "Disallow:")
+		}
+		section(lines)
+	}
+	section(formatLines(sitemaps))
+	section(pending)
+
+	return bufferedWriter.Flush()
+}
+
+// formatDirective normalizes the casing and spacing of a line that is not a comment.
+func formatDirective(rawLine string) formatLine {
+	line := formatLine{text: rawLine}
+	if position := strings.Index(rawLine, "#"); position != -1 {
+		line.text = strings.TrimSpace(rawLine[:position])
+		line.comment = rawLine[position:]
+	}
+
+	separator := strings.Index(line.text, ":")
+	if separator == -1 {
+		return line
+	}
+	key := strings.TrimSpace(line.text[:separator])
+	value := strings.TrimSpace(line.text[separator+1:])
+	if key == "" {
+		return line
+	}
+
+	line.key = strings.ToLower(key)
+	line.value = value
+	if canonicalKey, exists := canonicalKeys[line.key]; exists {
+		key = canonicalKey
+	}
+	line.text = key + ":"
+	if value != "" {
+		line.text += " " + value
+	}
+	return line
+}
+
+// appendUnique appends a line unless a line with the same text is already there, the comments of a line that is left out are
+// added to the one that is kept.
+func appendUnique(lines []formatLine, line formatLine) []formatLine {
+	for i := range lines {
+		if lines[i].text != line.text || lines[i].key == "" {
+			continue
+		}
+		lines[i].comments = append(lines[i].comments, line.comments...)
+		if line.comment != "" {
+			lines[i].comments = append(lines[i].comments, line.comment)
+		}
+		return lines
+	}
+	return append(lines, line)
+}
+
+// appendLast appends a line and leaves out an earlier line with the same text, the crawl delay that is defined last is the one that
+// is used.
+func appendLast(lines []formatLine, line formatLine) []formatLine {
+	for i := range lines {
+		if lines[i].text != line.text {
+			continue
+		}
+		earlier := lines[i]
+		line.comments = append(earlier.comments, line.comments...)
+		if earlier.comment != "" && line.comment == "" {
+			line.comment = earlier.comment
+		} else if earlier.comment != "" {
+			line.comments = append(line.comments, earlier.comment)
+		}
+		lines = append(lines[:i:i], lines[i+1:]...)
+		break
+	}
+	return append(lines, line)
+}
+
+func formatLines(lines []formatLine) []string {
+	var formatted []string
+	for _, line := range lines {
+		formatted = append(formatted, line.comments...)
+		if line.comment == "" {
+			formatted = append(formatted, line.text)
+		} else {
+			formatted = append(formatted, line.text+" "+line.comment)
+		}
+	}
+	return formatted
+}
+
+func hasRule(lines []formatLine) bool {
+	for _, line := range lines {
+		if line.key == "allow" || line.key == "disallow" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,144 @@
+package robotstxt_test
+
+import (
+	"errors"
+	"github.com/itmayziii/robotstxt/v2"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	formatted := format(t, `# Robots.txt for dumpsters.com
+   # Generated by hand
+
+# Everyone
+user-agent : *
+Disallow:*/retail/
+sitemap: https://www.dumpsters.com/sitemap.xml
+DISALLOW: /cms/   # SPA
+Crawl-delay:5
+# Repeated
+Disallow:   */retail/
+Host: www.dumpsters.com
+
+
+User-agent: AdsBot-Google
+Sitemap: https://www.dumpsters.com/sitemap.xml
+# Bing checks ads too
+User-agent: AdsBot-Bing
+Allow: /
+# The end`)
+
+	assert.Equal(t, `# Robots.txt for dumpsters.com
+# Generated by hand
+
+# Everyone
+User-agent: *
+Crawl-delay: 5
+# Repeated
+Disallow: */retail/
+Disallow: /cms/ # SPA
+Host: www.dumpsters.com
+
+User-agent: AdsBot-Google
+Disallow:
+
+# Bing checks ads too
+User-agent: AdsBot-Bing
+Allow: /
+
+Sitemap: https://www.dumpsters.com/sitemap.xml
+
+# The end
+`, formatted)
+	assert.Equal(t, formatted, format(t, formatted))
+}
+
+func TestFormat_example_robots_txt(t *testing.T) {
+	source, err := ioutil.ReadAll(getExampleRobotsTxt())
+	assert.Nil(t, err)
+	formatted := format(t, string(source))
+
+	assert.Contains(t, formatted, "# Indented comments are allowed\n\nUser-agent: *\nCrawl-delay: 5\n")
+	assert.Contains(t, formatted, "Disallow: */retail/*/frontend/*\nAllow: /be/fr_fr/retail/fr/\n")
+	assertSameRules(t, string(source), formatted)
+}
+
+func TestFormat_keeps_what_is_allowed(t *testing.T) {
+	tests := map[string]string{
+		"last crawl delay":        "User-agent: *\nCrawl-delay: 5\nCrawl-delay: 3\nCrawl-delay: 5\nDisallow: /cms/\n",
+		"empty user agent":        "User-agent: *\nDisallow: /cms/\nUser-agent:\nDisallow: /pricing/\n",
+		"rules before any agent":  "Disallow: /\nSitemap: https://www.dumpsters.com/sitemap.xml\nUser-agent: *\nDisallow: /cms/\n",
+		"sitemap between agents":  "User-agent: googlebot\nSitemap: https://www.dumpsters.com/sitemap.xml\nUser-agent: bingbot\nDisallow: /\n",
+		"sitemap inside of rules": "User-agent: googlebot\nDisallow: /cms/\nSitemap: https://www.dumpsters.com/sitemap.xml\nDisallow: /pricing/\n",
+		"repeated agents":         "User-agent: googlebot\nuser-agent: googlebot\nDisallow: /cms/\nUser-agent: googlebot\nAllow: /\n",
+	}
+	for name, source := range tests {
+		formatted := format(t, source)
+		assertSameRules(t, source, formatted)
+		assert.Equal(t, formatted, format(t, formatted), name)
+	}
+
+	// Random robots.txt files with the mistakes that are made by hand.
+	random := rand.New(rand.NewSource(1))
+	lines := []string{
+		"User-agent: *", "user-agent : googlebot", "User-agent: bingbot", "Disallow: /cms/", "disallow:/cms/", "Allow: /cms/public/",
+		"Disallow: *?s=lightbox", "Disallow:", "Crawl-delay: 5", "crawl-delay:2", "Sitemap: https://www.dumpsters.com/sitemap.xml",
+		"# Comment", "", "Host: www.dumpsters.com", "Disallow: /pricing/ # Pricing",
+	}
+	for round := 0; round < 200; round++ {
+		var source []string
+		for i := random.Intn(15); i >= 0; i-- {
+			source = append(source, lines[random.Intn(len(lines))])
+		}
+		formatted := format(t, strings.Join(source, "\n"))
+		assertSameRules(t, strings.Join(source, "\n"), formatted)
+		assert.Equal(t, formatted, format(t, formatted))
+	}
+}
+
+func TestFormat_invalid_encoding(t *testing.T) {
+	var builder strings.Builder
+	err := robotstxt.Format(strings.NewReader("User-agent: *\nDisallow: /\xff\n"), &builder)
+	var encodingError *robotstxt.EncodingError
+	assert.True(t, errors.As(err, &encodingError))
+	assert.Equal(t, 2, encodingError.Line)
+}
+
+func format(t *testing.T, source string) string {
+	var builder strings.Builder
+	assert.Nil(t, robotstxt.Format(strings.NewReader(source), &builder))
+	return builder.String()
+}
+
+// assertSameRules asserts that a formatted robots.txt is read the same as its source.
+func assertSameRules(t *testing.T, source, formatted string) {
+	before, err := robotstxt.New("https://www.dumpsters.com", strings.NewReader(source))
+	assert.Nil(t, err)
+	after, err := robotstxt.New("https://www.dumpsters.com", strings.NewReader(formatted))
+	assert.Nil(t, err)
+
+	agents := []string{"googlebot", "bingbot", "adsbot-google", "adsbot-bing"}
+	urls := []string{"/", "/cms/", "/cms/public/", "/pricing/", "/pricing?s=lightbox", "/be/fr_fr/retail/fr/frontend/"}
+	for _, agent := range agents {
+		assert.Equal(t, before.CanCrawlBatch(agent, urls), after.CanCrawlBatch(agent, urls), source)
+		assert.Equal(t, before.CrawlDelay(agent), after.CrawlDelay(agent), source)
+	}
+	assert.Equal(t, uniqueStrings(before.Sitemaps()), after.Sitemaps())
+	assert.Equal(t, len(before.Groups()), len(after.Groups()), source)
+}
+
+func uniqueStrings(values []string) []string {
+	var unique []string
+	seen := make(map[string]bool)
+	for _, value := range values {
+		if !seen[value] {
+			unique = append(unique, value)
+			seen[value] = true
+		}
+	}
+	return unique
+}
@@ -0,0 +1,180 @@
+package robotstxt
+
+import (
+	"strings"
+)
+
+/*
+ruleIndex finds the most specific allow and disallow directive for a path without looking at every directive of a group. Directives
+without a "*" or a "$" at their end are stored in a radix tree keyed by their path, walking a path down the tree visits only the
+directives the path starts with. The other directives are matched by one automaton that reads the path a single time for all of
+them:
+
+	Disallow: /cms/               -> tree
+	Disallow: /products/*.pdf$    -> automaton
+	Disallow: *?s=lightbox        -> automaton
+
+Every byte other than "*" and a "$" at the end of a directive only matches itself, i.e. "." and "?" are not special.
+*/
+type ruleIndex struct {
+	root      indexNode
+	wildcards *automaton // Nil when there are no wildcard directives.
+}
+
+type indexNode struct {
+	label    string
+	children []*indexNode // Sorted by the first byte of their label.
+
+	// Directives without wildcards that end at this node.
+	allow    string
+	disallow string
+}
+
+// maxIndexStates is how many states the automaton of an index keeps, paths that need more still match but build them every time.
+const maxIndexStates = 4096
+
+// newRuleIndex indexes the directives of a group.
+func newRuleIndex(allowed, disallowed []string) *ruleIndex {
+	index := &ruleIndex{}
+	var wildcards []Rule
+	for _, path := range disallowed {
+		if wildcardPattern(path) {
+			wildcards = append(wildcards, Rule{Path: path})
+			continue
+		}
+		index.root.insert(path).disallow = path
+	}
+	for _, path := range allowed {
+		if wildcardPattern(path) {
+			wildcards = append(wildcards, Rule{Allow: true, Path: path})
+			continue
+		}
+		index.root.insert(path).allow = path
+	}
+	if len(wildcards) > 0 {
+		index.wildcards = newAutomaton(wildcards)
+		index.wildcards.limit = maxIndexStates
+	}
+	return index
+}
+
+// wildcardPattern reports whether a path has a "*" or ends with "$", those are the only bytes that do not match themselves.
+func wildcardPattern(path string) bool {
+	return strings.Contains(path, "*") || strings.HasSuffix(path, "$")
+}
+
+// match returns the longest allow and disallow directives that match the path, an empty string means nothing matched.
+func (index *ruleIndex) match(path string) (string, string) {
+	allowed, disallowed := "", ""
+	if index.wildcards != nil {
+		state := index.wildcards.walk(path)
+		if state.allow != -1 {
+			allowed = index.wildcards.rules[state.allow].Path
+		}
+		if state.disallow != -1 {
+			disallowed = index.wildcards.rules[state.disallow].Path
+		}
+	}
+
+	node := &index.root
+	matched := 0
+	for {
+		if len(node.allow) > len(allowed) {
+			allowed = node.allow
+		}
+		if len(node.disallow) > len(disallowed) {
+			disallowed = node.disallow
+		}
+
+		node = node.child(path[matched:])
+		if node == nil {
+			return allowed, disallowed
+		}
+		matched += len(node.label)
+	}
+}
+
+// size is a rough estimate of how much memory the index uses on top of the directives themselves.
+func (index *ruleIndex) size() int64 {
+	const overhead = 16
+	var size func(node *indexNode) int64
+	size = func(node *indexNode) int64 {
+		total := int64(len(node.label) + 6*overhead)
+		for _, child := range node.children {
+			total += size(child)
+		}
+		return total
+	}
+	total := size(&index.root)
+	if index.wildcards != nil {
+		// Every step of a pattern takes a few words, states are built as paths need them and are counted as they would be once
+		// every step is part of a few of them.
+		total += int64(len(index.wildcards.steps)) * (4 * overhead)
+	}
+	return total
+}
+
+// insert returns the node for a literal prefix, splitting labels along the way when needed.
+func (node *indexNode) insert(prefix string) *indexNode {
+	for prefix != "" {
+		position := node.search(prefix[0])
+		if position == len(node.children) || node.children[position].label[0] != prefix[0] {
+			child := &indexNode{label: prefix}
+			node.children = append(node.children, nil)
+			copy(node.children[position+1:], node.children[position:])
+			node.children[position] = child
+			return child
+		}
+
+		child := node.children[position]
+		common := commonPrefixLength(prefix, child.label)
+		if common < len(child.label) {
+			// The prefix ends in the middle of the label, the label is split so the prefix has a node to end at.
+			split := &indexNode{label: child.label[:common], children: []*indexNode{child}}
+			child.label = child.label[common:]
+			node.children[position] = split
+			child = split
+		}
+		node = child
+		prefix = prefix[common:]
+	}
+	return node
+}
+
+// child returns the child whose label the path starts with, nil is returned when there is no such child.
+func (node *indexNode) child(path string) *indexNode {
+	if path == "" {
+		return nil
+	}
+	position := node.search(path[0])
+	if position == len(node.children) {
+		return nil
+	}
+	child := node.children[position]
+	if !strings.HasPrefix(path, child.label) {
+		return nil
+	}
+	return child
+}
+
+// search returns the position of the child whose label starts with the byte or where it would have to be inserted.
+func (node *indexNode) search(b byte) int {
+	low, high := 0, len(node.children)
+	for low < high {
+		middle := (low + high) / 2
+		if node.children[middle].label[0] < b {
+			low = middle + 1
+		} else {
+			high = middle
+		}
+	}
+	return low
+}
+
+func commonPrefixLength(a, b string) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return i
+}
@@ -0,0 +1,137 @@
+package robotstxt
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+)
+
+func TestRuleIndex_match(t *testing.T) {
+	index := newRuleIndex(
+		[]string{"/cms/public/", "/*.pdf$", "/page?id*", "/search?q="},
+		[]string{"/cms/", "/cms/public/drafts", "*?s=lightbox", "/se/en$", "/products/*/print"},
+	)
+
+	tests := []struct {
+		path       string
+		allowed    string
+		disallowed string
+	}{
+		{path: "/", allowed: "", disallowed: ""},
+		{path: "/cms/pages", allowed: "", disallowed: "/cms/"},
+		{path: "/cms/public/page", allowed: "/cms/public/", disallowed: "/cms/"},
+		{path: "/cms/public/drafts/1", allowed: "/cms/public/", disallowed: "/cms/public/drafts"},
+		{path: "/cms/brochure.pdf", allowed: "/*.pdf$", disallowed: "/cms/"},
+		{path: "/cms/brochure.pdf?print=1", allowed: "", disallowed: "/cms/"},
+		{path: "/pricing?s=lightbox", allowed: "", disallowed: "*?s=lightbox"},
+		{path: "/pricing?cart=full&s=lightbox", allowed: "", disallowed: ""},
+		{path: "/se/en", allowed: "", disallowed: "/se/en$"},
+		{path: "/se/en/", allowed: "", disallowed: ""},
+		{path: "/products/123/print", allowed: "", disallowed: "/products/*/print"},
+		{path: "/store/products/123/print", allowed: "", disallowed: ""},
+		{path: "/page?id=1", allowed: "/page?id*", disallowed: ""},
+		{path: "/pagid", allowed: "", disallowed: ""},
+		{path: "/search?q=dumpsters", allowed: "/search?q=", disallowed: ""},
+		{path: "/searchq=dumpsters", allowed: "", disallowed: ""},
+	}
+	for _, test := range tests {
+		allowed, disallowed := index.match(test.path)
+		assert.Equal(t, test.allowed, allowed, test.path)
+		assert.Equal(t, test.disallowed, disallowed, test.path)
+	}
+}
+
+// Only "*" and a "$" at the end have a meaning, every other byte of a pattern matches itself.
+func TestRuleIndex_match_special_characters(t *testing.T) {
+	index := newRuleIndex(nil, []string{"/*.pdf$", "/page?id=*", "/search/(*", "/*[draft]", "/a+b*", "/price$/*"})
+
+	tests := []struct {
+		path       string
+		disallowed string
+	}{
+		{path: "/brochure.pdf", disallowed: "/*.pdf$"},
+		{path: "/products/pdf", disallowed: ""},
+		{path: "/xpdf", disallowed: ""},
+		{path: "/page?id=1", disallowed: "/page?id=*"},
+		{path: "/pagid=1", disallowed: ""},
+		{path: "/pageid=1", disallowed: ""},
+		{path: "/search/(dumpsters)", disallowed: "/search/(*"},
+		{path: "/search/dumpsters", disallowed: ""},
+		{path: "/cms/[draft]", disallowed: "/*[draft]"},
+		{path: "/cms/d", disallowed: ""},
+		{path: "/a+b", disallowed: "/a+b*"},
+		{path: "/aab", disallowed: ""},
+		{path: "/price$/list", disallowed: "/price$/*"},
+		{path: "/price", disallowed: ""},
+	}
+	for _, test := range tests {
+		_, disallowed := index.match(test.path)
+		assert.Equal(t, test.disallowed, disallowed, test.path)
+	}
+}
+
+// The index has to give the same answers as checking every directive one by one.
+func TestRuleIndex_match_agrees_with_linear_scan(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	pattern := func() string {
+		length := 1 + random.Intn(6)
+		path := "/"
+		if random.Intn(5) == 0 {
+			path = "*"
+		}
+		for i := 0; i < length; i++ {
+			path += string("ab/.*?$("[random.Intn(8)])
+		}
+		if random.Intn(4) == 0 {
+			path += "$"
+		}
+		return path
+	}
+
+	for round := 0; round < 200; round++ {
+		var allowed, disallowed []string
+		for i := random.Intn(20); i >= 0; i-- {
+			allowed = append(allowed, pattern())
+			disallowed = append(disallowed, pattern())
+		}
+		index := newRuleIndex(allowed, disallowed)
+
+		for i := 0; i < 50; i++ {
+			path := "/"
+			for j := random.Intn(8); j > 0; j-- {
+				path += string("ab/.?$("[random.Intn(7)])
+			}
+			indexAllowed, indexDisallowed := index.match(path)
+			assert.Equal(t, len(linearMatch(path, allowed)), len(indexAllowed), "%s %v", path, allowed)
+			assert.Equal(t, len(linearMatch(path, disallowed)), len(indexDisallowed), "%s %v", path, disallowed)
+		}
+	}
+}
+
+func linearMatch(path string, patterns []string) string {
+	longest := ""
+	for _, pattern := range patterns {
+		if patternMatches(pattern, path) && len(pattern) > len(longest) {
+			longest = pattern
+		}
+	}
+	return longest
+}
+
+// patternMatches matches a pattern the way it is defined, "*" is any number of bytes and a "$" at the end is the end of the path.
+func patternMatches(pattern, path string) bool {
+	switch {
+	case pattern == "":
+		return true
+	case pattern == "$":
+		return path == ""
+	case pattern[0] == '*':
+		for i := 0; i <= len(path); i++ {
+			if patternMatches(pattern[1:], path[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	return path != "" && path[0] == pattern[0] && patternMatches(pattern[1:], path[1:])
+}
@@ -0,0 +1,208 @@
+package robotstxt
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// defaultMaxCrawlDelay protects a Limiter against hostile values such as "Crawl-delay: 86400".
+const defaultMaxCrawlDelay = 30 * time.Second
+
+/*
+Limiter spaces out requests to each origin based on the Crawl-delay that the robots.txt of the origin declares for RobotName, it
+takes care of the throttling every caller of CrawlDelay would otherwise build for themselves:
+
+	limiter := &robotstxt.Limiter{RobotName: "dumpsterbot", DefaultDelay: time.Second, MaxConcurrent: 2}
+	err := limiter.Wait(ctx, "https://www.dumpsters.com/pricing/")
+	if err != nil {
+		return err
+	}
+	defer limiter.Done("https://www.dumpsters.com/pricing/")
+	// Make the request.
+
+DefaultDelay is used for origins that do not declare a Crawl-delay and every delay is capped at MaxDelay. When MaxConcurrent is set
+no more than that many requests per origin can be between Wait and Done at the same time.
+
+The spacing is only enforced between the callers of a single Limiter unless RateStore is shared, i.e. through a RemoteRateStore so
+several crawler processes share the budget of each host. MaxConcurrent is always local to a Limiter.
+
+The zero value is ready to use and a Limiter is safe for concurrent use.
+*/
+type Limiter struct {
+	// Robots retrieves the robots.txt of each origin, a Registry is used when nil.
+	Robots Getter
+
+	// RobotName is the robot (user-agent) whose Crawl-delay is used.
+	RobotName string
+
+	// DefaultDelay is the delay for origins that do not declare a Crawl-delay.
+	DefaultDelay time.Duration
+
+	// MaxDelay caps the delay of every origin, 30 seconds is used when zero.
+	MaxDelay time.Duration
+
+	// MaxConcurrent is the maximum number of requests per origin between Wait and Done, there is no limit when zero.
+	MaxConcurrent int
+
+	// RateStore hands out the request slots of each origin, a MemoryRateStore is used when nil.
+	RateStore RateStore
+
+	once      sync.Once
+	robots    Getter
+	rateStore RateStore
+	mu        sync.Mutex
+	hosts     map[string]*limiterHost
+}
+
+type limiterHost struct {
+	semaphore chan struct{}
+	users     int
+}
+
+// Wait blocks until a request to the origin of the URL is allowed, it returns early with an error when ctx is done. Every successful
+// Wait must be followed by a call to Done once the request finished when MaxConcurrent is set.
+func (limiter *Limiter) Wait(ctx context.Context, url string) error {
+	origin, err := normalizeUrl(url)
+	if err != nil {
+		return err
+	}
+
+	delay, err := limiter.Delay(ctx, url)
+	if err != nil {
+		return err
+	}
+
+	host := limiter.acquireHost(origin)
+	if limiter.MaxConcurrent > 0 {
+		select {
+		case host.semaphore <- struct{}{}:
+		case <-ctx.Done():
+			limiter.releaseHost(origin, host)
+			return ctx.Err()
+		}
+	}
+
+	at, err := limiter.rateStore.Reserve(ctx, origin, delay)
+	if err == nil {
+		err = limiter.sleep(ctx, at)
+	}
+	if limiter.MaxConcurrent <= 0 {
+		// Nothing is held until Done without MaxConcurrent, Done does not know about the origin either.
+		limiter.releaseHost(origin, host)
+		return err
+	}
+	if err != nil {
+		limiter.Done(url)
+		return err
+	}
+	return nil
+}
+
+// Done marks a request to the origin of the URL as finished, it frees up a spot for MaxConcurrent.
+func (limiter *Limiter) Done(url string) {
+	if limiter.MaxConcurrent <= 0 {
+		return
+	}
+	origin, err := normalizeUrl(url)
+	if err != nil {
+		return
+	}
+
+	limiter.mu.Lock()
+	host, exists := limiter.hosts[origin]
+	limiter.mu.Unlock()
+	if !exists {
+		return
+	}
+
+	select {
+	case <-host.semaphore:
+		limiter.releaseHost(origin, host)
+	default:
+		// Done was called more times than Wait.
+	}
+}
+
+// Delay is the spacing the Limiter enforces between requests to the origin of the URL.
+func (limiter *Limiter) Delay(ctx context.Context, url string) (time.Duration, error) {
+	robotsTxt, err := limiter.getter().Get(ctx, url)
+	if err != nil {
+		return 0, err
+	}
+
+	delay := robotsTxt.CrawlDelay(limiter.RobotName)
+	if delay <= 0 {
+		delay = limiter.DefaultDelay
+	}
+	maxDelay := limiter.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = defaultMaxCrawlDelay
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay, nil
+}
+
+func (limiter *Limiter) sleep(ctx context.Context, until time.Time) error {
+	wait := until.Sub(timeNow())
+	if wait <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// acquireHost returns the state of an origin and marks it as in use so it is not cleaned up.
+func (limiter *Limiter) acquireHost(origin string) *limiterHost {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	if limiter.hosts == nil {
+		limiter.hosts = make(map[string]*limiterHost)
+	}
+	host, exists := limiter.hosts[origin]
+	if !exists {
+		host = &limiterHost{semaphore: make(chan struct{}, limiter.MaxConcurrent)}
+		limiter.hosts[origin] = host
+	}
+	host.users++
+	return host
+}
+
+// releaseHost forgets about an origin once nobody uses it, otherwise the Limiter would hold on to every origin it has ever seen.
+func (limiter *Limiter) releaseHost(origin string, host *limiterHost) {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	host.users--
+	if host.users == 0 {
+		delete(limiter.hosts, origin)
+	}
+}
+
+func (limiter *Limiter) getter() Getter {
+	limiter.init()
+	return limiter.robots
+}
+
+func (limiter *Limiter) init() {
+	limiter.once.Do(func() {
+		limiter.robots = limiter.Robots
+		if limiter.robots == nil {
+			limiter.robots = &Registry{Getter: &Fetcher{UserAgent: limiter.RobotName}}
+		}
+		limiter.rateStore = limiter.RateStore
+		if limiter.rateStore == nil {
+			limiter.rateStore = &MemoryRateStore{}
+		}
+	})
+}
@@ -0,0 +1,34 @@
+package robotstxt
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+// An origin is forgotten after a Wait that fails, otherwise every cancelled request would leak an entry.
+func TestLimiter_Wait_releases_hosts(t *testing.T) {
+	for _, maxConcurrent := range []int{0, 2} {
+		limiter := &Limiter{
+			Robots: GetterFunc(func(ctx context.Context, url string) (*RobotsTxt, error) {
+				return New(url, strings.NewReader("User-agent: *\nCrawl-delay: 10\n"))
+			}),
+			MaxConcurrent: maxConcurrent,
+		}
+		assert.Nil(t, limiter.Wait(context.Background(), "https://www.dumpsters.com/"))
+		limiter.Done("https://www.dumpsters.com/")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		assert.Equal(t, context.DeadlineExceeded, limiter.Wait(ctx, "https://www.dumpsters.com/"))
+		cancel()
+		ctx, cancel = context.WithCancel(context.Background())
+		cancel()
+		assert.Equal(t, context.Canceled, limiter.Wait(ctx, "https://www.dumpsters.com/"))
+
+		limiter.mu.Lock()
+		assert.Empty(t, limiter.hosts, "MaxConcurrent %d", maxConcurrent)
+		limiter.mu.Unlock()
+	}
+}
@@ -0,0 +1,101 @@
+package robotstxt_test
+
+import (
+	"context"
+	"github.com/itmayziii/robotstxt/v2"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLimiter_Wait_spaces_out_requests(t *testing.T) {
+	limiter := &robotstxt.Limiter{Robots: robotsTxtGetter(""), DefaultDelay: 50 * time.Millisecond}
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Nil(t, limiter.Wait(context.Background(), "https://www.dumpsters.com/pricing/"))
+		}()
+	}
+	wg.Wait()
+	assert.True(t, time.Since(start) >= 150*time.Millisecond)
+
+	// Other origins have their own budget.
+	start = time.Now()
+	assert.Nil(t, limiter.Wait(context.Background(), "https://www.budgetdumpster.com/"))
+	assert.True(t, time.Since(start) < 50*time.Millisecond)
+}
+
+func TestLimiter_Delay(t *testing.T) {
+	limiter := &robotstxt.Limiter{Robots: robotsTxtGetter("User-agent: *\nCrawl-delay: 2\n"), RobotName: "dumpsterbot"}
+	delay, err := limiter.Delay(context.Background(), "https://www.dumpsters.com")
+	assert.Nil(t, err)
+	assert.Equal(t, 2*time.Second, delay)
+
+	limiter = &robotstxt.Limiter{Robots: robotsTxtGetter(""), DefaultDelay: time.Second}
+	delay, err = limiter.Delay(context.Background(), "https://www.dumpsters.com")
+	assert.Nil(t, err)
+	assert.Equal(t, time.Second, delay)
+
+	limiter = &robotstxt.Limiter{Robots: robotsTxtGetter("User-agent: *\nCrawl-delay: 86400\n")}
+	delay, err = limiter.Delay(context.Background(), "https://www.dumpsters.com")
+	assert.Nil(t, err)
+	assert.Equal(t, 30*time.Second, delay)
+
+	limiter = &robotstxt.Limiter{Robots: robotsTxtGetter("User-agent: *\nCrawl-delay: 86400\n"), MaxDelay: time.Minute}
+	delay, err = limiter.Delay(context.Background(), "https://www.dumpsters.com")
+	assert.Nil(t, err)
+	assert.Equal(t, time.Minute, delay)
+}
+
+func TestLimiter_Wait_limits_concurrency(t *testing.T) {
+	limiter := &robotstxt.Limiter{Robots: robotsTxtGetter(""), MaxConcurrent: 2}
+
+	var active, maxActive int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Nil(t, limiter.Wait(context.Background(), "https://www.dumpsters.com/"))
+			defer limiter.Done("https://www.dumpsters.com/")
+
+			current := atomic.AddInt32(&active, 1)
+			for {
+				seen := atomic.LoadInt32(&maxActive)
+				if current <= seen || atomic.CompareAndSwapInt32(&maxActive, seen, current) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(&active, -1)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&maxActive))
+}
+
+func TestLimiter_Wait_respects_context(t *testing.T) {
+	limiter := &robotstxt.Limiter{Robots: robotsTxtGetter("User-agent: *\nCrawl-delay: 10\n")}
+	assert.Nil(t, limiter.Wait(context.Background(), "https://www.dumpsters.com/"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	assert.Equal(t, context.DeadlineExceeded, limiter.Wait(ctx, "https://www.dumpsters.com/"))
+	assert.True(t, time.Since(start) < time.Second)
+}
+
+// robotsTxtGetter serves the same robots.txt for every origin.
+func robotsTxtGetter(robotsTxt string) robotstxt.Getter {
+	return robotstxt.GetterFunc(func(ctx context.Context, url string) (*robotstxt.RobotsTxt, error) {
+		return robotstxt.New(url, strings.NewReader(robotsTxt))
+	})
+}
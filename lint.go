@@ -0,0 +1,358 @@
+package robotstxt
+
+import (
+	"io"
+	"math"
+	netUrl "net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxLintSize is the size after which Google ignores the rest of a robots.txt,
+// https://developers.google.com/search/docs/crawling-indexing/robots/robots_txt#file-format.
+const maxLintSize = 500 * 1024
+
+// regExpCharacters look like part of a regular expression in a pattern, but a pattern only treats "*" and a "$" at its end specially
+// and matches every other byte as it is.
+const regExpCharacters = `\()|[]{}+^`
+
+// escapedPathCharacters are the bytes of pathAlphabet that a URL escapes in its path, only its query keeps them as they are.
+const escapedPathCharacters = "\\\"<>^`{|}"
+
+// Severity is how bad a Finding is.
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+// String returns "info", "warning", or "error".
+func (severity Severity) String() string {
+	switch severity {
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	}
+	return "info"
+}
+
+// LintRule is a problem Lint looks for.
+type LintRule struct {
+	// Code identifies the rule, i.e. "RT001".
+	Code string
+
+	// Severity of the findings of the rule.
+	Severity Severity
+
+	// Summary describes the problem in a few words.
+	Summary string
+}
+
+// LintRules are all the problems Lint looks for.
+var LintRules = []LintRule{
+	{Code: "RT001", Severity: SeverityWarning, Summary: "directive before any User-agent"},
+	{Code: "RT002", Severity: SeverityError, Summary: "path does not start with a slash"},
+	{Code: "RT003", Severity: SeverityWarning, Summary: "rule is shadowed by another rule or can never match"},
+	{Code: "RT004", Severity: SeverityWarning, Summary: "allow and disallow of the same path cancel out"},
+	{Code: "RT005", Severity: SeverityWarning, Summary: "user agent is part of more than one group"},
+	{Code: "RT006", Severity: SeverityError, Summary: "sitemap is not an absolute URL"},
+	{Code: "RT007", Severity: SeverityError, Summary: "invalid crawl-delay"},
+	{Code: "RT008", Severity: SeverityWarning, Summary: "unsupported directive"},
+	{Code: "RT009", Severity: SeverityError, Summary: "line is not a directive"},
+	{Code: "RT010", Severity: SeverityError, Summary: "file is larger than 500 KiB"},
+	{Code: "RT011", Severity: SeverityWarning, Summary: "pattern with regular expression characters"},
+	{Code: "RT012", Severity: SeverityError, Summary: "pattern with characters that URLs escape"},
+	{Code: "RT013", Severity: SeverityError, Summary: "line is not valid UTF-8"},
+}
+
+// Finding is a problem Lint found on a line.
+type Finding struct {
+	// Code is the code of the LintRule that found the problem.
+	Code string
+
+	// Line is the line number, starting at 1.
+	Line int
+
+	// Severity is the severity of the LintRule.
+	Severity Severity
+
+	// Message describes the problem.
+	Message string
+
+	// Fix suggests how to solve the problem.
+	Fix string
+}
+
+// String returns the finding as "line 3: warning RT001: message".
+func (finding Finding) String() string {
+	return "line " + strconv.Itoa(finding.Line) + ": " + finding.Severity.String() + " " + finding.Code + ": " + finding.Message
+}
+
+// lintRuleLine is an allow or disallow directive of a group.
+type lintRuleLine struct {
+	Rule
+	line int
+}
+
+/*
+Lint checks a robots.txt for mistakes, only an error of the reader makes it fail. Every Finding has the line it is on and a
+suggestion to fix it, findings are sorted by line:
+
+	findings, err := robotstxt.Lint(strings.NewReader("Disallow: /cms/\nUser-agent: *\nDisallow: cms/\n"))
+	// line 1: warning RT001: Disallow before any User-agent is ignored
+	// line 3: error RT002: path "cms/" does not start with "/" or "*" so it never matches
+
+See LintRules for everything that is checked.
+*/
+func Lint(reader io.Reader) ([]Finding, error) {
+	file, err := ParseFile(reader)
+	if err != nil {
+		return nil, err
+	}
+	return file.Lint(), nil
+}
+
+// Lint checks the File for mistakes, see Lint.
+func (file *File) Lint() []Finding {
+	linter := &linter{}
+	size := 0
+	for i, line := range file.lines {
+		size += len(line.raw) + len(line.ending)
+		if size > maxLintSize {
+			linter.add("RT010", i+1, "the file is larger than 500 KiB, crawlers ignore everything after this line",
+				"remove rules that are not needed or combine them with \"*\"")
+			break
+		}
+	}
+
+	starts := file.groupStarts()
+	groups := make([][]lintRuleLine, len(starts))
+	agentLines := make(map[string]int) // The line a user agent is first named on.
+	agentGroups := make(map[string]int)
+	group := -1
+	for i, line := range file.lines {
+		number := i + 1
+		if group+1 < len(starts) && starts[group+1] == i {
+			group++
+		}
+
+		if !utf8.ValidString(line.raw) {
+			linter.add("RT013", number, "the line is not valid UTF-8 so the robots.txt can not be read",
+				"save the file as UTF-8")
+			continue
+		}
+
+		switch line.kind {
+		case InvalidLine:
+			if strings.Contains(line.raw, ":") && !strings.HasPrefix(strings.TrimSpace(line.raw), "#") {
+				linter.add("RT009", number, "the line has no key before its \":\" so it is ignored", "add a key or remove the line")
+				continue
+			}
+			linter.add("RT009", number, "the line is not a comment and has no \":\" between a key and a value so it is ignored",
+				"add a \":\" after the key or start the line with \"#\" to make it a comment")
+		case UnknownLine:
+			linter.add("RT008", number, strconv.Quote(line.key)+" is not supported by this package and ignored by most crawlers",
+				"remove the line or make it a comment")
+		case UserAgentLine:
+			if line.value == "" {
+				linter.add("RT009", number, "User-agent without a value is ignored", "add the name of a robot or \"*\"")
+				continue
+			}
+			agent := strings.ToLower(line.value)
+			if first, exists := agentLines[agent]; exists && agentGroups[agent] == group {
+				linter.add("RT005", number, "user agent "+strconv.Quote(line.value)+" is already named on line "+strconv.Itoa(first),
+					"remove the line")
+			} else if exists {
+				linter.add("RT005", number, "user agent "+strconv.Quote(line.value)+" is also part of the group on line "+
+					strconv.Itoa(first)+", only this group is used for it", "merge the two groups into one")
+			}
+			agentLines[agent] = number
+			agentGroups[agent] = group
+		case AllowLine, DisallowLine:
+			key := canonicalKeys[line.key]
+			if group == -1 {
+				linter.add("RT001", number, key+" before any User-agent is ignored", "add a User-agent line above it")
+			}
+			if line.value == "" {
+				continue
+			}
+			if linter.lintPath(number, key, line.value) && group != -1 {
+				rule := Rule{Allow: line.kind == AllowLine, Path: line.value}
+				groups[group] = append(groups[group], lintRuleLine{Rule: rule, line: number})
+			}
+		case CrawlDelayLine:
+			if group == -1 {
+				linter.add("RT001", number, "Crawl-delay before any User-agent is ignored", "add a User-agent line above it")
+			}
+			seconds, err := strconv.Atoi(line.value)
+			if err != nil || seconds < 0 {
+				fix := "use a whole number of seconds, i.e. \"Crawl-delay: 5\""
+				if value, err := strconv.ParseFloat(line.value, 64); err == nil && value > 0 {
+					fix = "round it up to whole seconds, \"Crawl-delay: " + strconv.FormatFloat(math.Ceil(value), 'f', 0, 64) + "\""
+				}
+				linter.add("RT007", number, "crawl-delay "+strconv.Quote(line.value)+" is not a whole number of seconds", fix)
+			}
+		case SitemapLine:
+			parsedUrl, err := netUrl.Parse(line.value)
+			if err != nil || (parsedUrl.Scheme != "http" && parsedUrl.Scheme != "https") || parsedUrl.Host == "" {
+				linter.add("RT006", number, "sitemap "+strconv.Quote(line.value)+" is not an absolute http(s) URL",
+					"use the full URL, i.e. \"Sitemap: https://www.example.com/sitemap.xml\"")
+			}
+		}
+	}
+
+	for _, rules := range groups {
+		linter.lintGroup(rules)
+	}
+
+	sort.SliceStable(linter.findings, func(i, j int) bool {
+		return linter.findings[i].Line < linter.findings[j].Line
+	})
+	return linter.findings
+}
+
+type linter struct {
+	findings []Finding
+}
+
+func (linter *linter) add(code string, line int, message, fix string) {
+	finding := Finding{Code: code, Line: line, Message: message, Fix: fix}
+	for _, lintRule := range LintRules {
+		if lintRule.Code == code {
+			finding.Severity = lintRule.Severity
+		}
+	}
+	linter.findings = append(linter.findings, finding)
+}
+
+// lintPath checks the path of an allow or disallow directive and reports whether it can ever match.
+func (linter *linter) lintPath(line int, key, path string) bool {
+	if !strings.HasPrefix(path, "/") && !strings.HasPrefix(path, "*") {
+		linter.add("RT002", line, "path "+strconv.Quote(path)+" does not start with \"/\" or \"*\" so it never matches",
+			"use \""+key+": /"+path+"\"")
+		return false
+	}
+	if i := regExpIndex(path); i != -1 {
+		fix := "use \"*\" for any characters and \"$\" at the end for the end of the URL"
+		if path[i] == '\\' {
+			fix = "use \"" + key + ": " + strings.Replace(path, "\\", "", -1) + "\""
+		}
+		linter.add("RT011", line, "path "+strconv.Quote(path)+" has a "+strconv.Quote(path[i:i+1])+" that looks like a regular "+
+			"expression, patterns only treat \"*\" and a \"$\" at the end specially and match it as it is", fix)
+	}
+
+	// Whatever comes after a "?" or "*" can be part of the query, which is not escaped.
+	end := strings.IndexAny(path, "?*")
+	if end == -1 {
+		end = len(path)
+	}
+	escaped := escapePath(path[:end])
+	if escaped != path[:end] {
+		linter.add("RT012", line, "path "+strconv.Quote(path)+" has characters that URLs escape so it never matches",
+			"use \""+key+": "+escaped+path[end:]+"\"")
+		return false
+	}
+	return true
+}
+
+// regExpIndex returns the position of the first byte of a path that looks like a regular expression, -1 when there is none. A "$"
+// only looks like one before the end of the path.
+func regExpIndex(path string) int {
+	i := strings.IndexAny(path, regExpCharacters)
+	if end := strings.Index(path, "$"); end != -1 && end < len(path)-1 && (i == -1 || end < i) {
+		return end
+	}
+	return i
+}
+
+// escapePath escapes the bytes a URL escapes in its path the same way.
+func escapePath(path string) string {
+	var builder strings.Builder
+	for i := 0; i < len(path); i++ {
+		if strings.IndexByte(pathAlphabet, path[i]) == -1 || strings.IndexByte(escapedPathCharacters, path[i]) != -1 {
+			builder.WriteString("%" + strings.ToUpper(strconv.FormatUint(uint64(path[i]), 16)))
+			continue
+		}
+		builder.WriteByte(path[i])
+	}
+	return builder.String()
+}
+
+// lintGroup finds the allow and disallow directives of a group that never make a difference.
+func (linter *linter) lintGroup(rules []lintRuleLine) {
+	lines := make(map[Rule]int)
+	for _, rule := range rules {
+		key := "Disallow"
+		if rule.Allow {
+			key = "Allow"
+		}
+
+		if first, exists := lines[rule.Rule]; exists {
+			linter.add("RT003", rule.line, key+": "+rule.Path+" is the same as line "+strconv.Itoa(first), "remove the line")
+			continue
+		}
+		lines[rule.Rule] = rule.line
+
+		opposite := Rule{Allow: !rule.Allow, Path: rule.Path}
+		if other, exists := lines[opposite]; exists {
+			disallowLine := rule.line
+			if rule.Allow {
+				disallowLine = other
+			}
+			linter.add("RT004", disallowLine, "Allow and Disallow of "+strconv.Quote(rule.Path)+" on lines "+strconv.Itoa(other)+" and "+
+				strconv.Itoa(rule.line)+" cancel out, the allow always wins", "remove the Disallow or the Allow")
+		}
+	}
+
+	for _, rule := range rules {
+		if lines[rule.Rule] != rule.line || lines[Rule{Allow: !rule.Allow, Path: rule.Path}] != 0 {
+			continue
+		}
+		if shadow, exists := shadowingRule(rule.Rule, rules); exists {
+			linter.add("RT003", rule.line, rule.String()+" is shadowed by "+shadow.String()+" on line "+strconv.Itoa(shadow.line)+
+				", every path it matches is already decided the same way", "remove the line")
+		}
+	}
+}
+
+// shadowingRule finds a shorter directive of the same kind that makes a directive useless, that is when no directive of the other
+// kind could match in between them.
+func shadowingRule(rule Rule, rules []lintRuleLine) (lintRuleLine, bool) {
+	if strings.ContainsAny(rule.Path, "*$") {
+		return lintRuleLine{}, false
+	}
+
+	for _, shorter := range rules {
+		if shorter.Allow != rule.Allow || len(shorter.Path) >= len(rule.Path) || strings.ContainsAny(shorter.Path, "*$") ||
+			!strings.HasPrefix(rule.Path, shorter.Path) {
+			continue
+		}
+
+		inBetween := false
+		for _, other := range rules {
+			if other.Allow == rule.Allow || len(other.Path) < len(shorter.Path) || len(other.Path) >= len(rule.Path) {
+				continue
+			}
+			// A pattern only matches the paths of the directive when its literal start does.
+			literal := other.Path
+			if end := strings.IndexAny(literal, "*$"); end != -1 {
+				literal = literal[:end]
+				if strings.HasPrefix(literal, rule.Path) {
+					inBetween = true
+				}
+			}
+			if strings.HasPrefix(rule.Path, literal) {
+				inBetween = true
+			}
+		}
+		if !inBetween {
+			return shorter, true
+		}
+	}
+	return lintRuleLine{}, false
+}
@@ -0,0 +1,217 @@
+package robotstxt_test
+
+import (
+	"fmt"
+	"github.com/itmayziii/robotstxt/v2"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	findings, err := robotstxt.Lint(strings.NewReader(`Crawl-delay: 5
+User-agent: *
+Disallow: cms/
+Disallow: /cms/
+Disallow: /cms/pages/
+Disallow: /cms/
+Allow: /cms/
+Disallow: /a$b
+Disallow: /*.php$
+Disallow: /*\.html$
+Disallow: /*(
+Crawl-delay: 1.5
+Crawl-delay: -1
+Host: www.dumpsters.com
+not a directive
+
+User-agent: googlebot
+User-agent: Googlebot
+Disallow: /tmp/
+
+User-agent: *
+Disallow: /private/
+Sitemap: /sitemap.xml
+Sitemap: https://www.dumpsters.com/sitemap.xml
+`))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"line 1: warning RT001: Crawl-delay before any User-agent is ignored",
+		`line 3: error RT002: path "cms/" does not start with "/" or "*" so it never matches`,
+		`line 4: warning RT004: Allow and Disallow of "/cms/" on lines 4 and 7 cancel out, the allow always wins`,
+		"line 6: warning RT003: Disallow: /cms/ is the same as line 4",
+		`line 8: warning RT011: path "/a$b" has a "$" that looks like a regular expression, patterns only treat "*" and a "$" at the ` +
+			`end specially and match it as it is`,
+		`line 10: warning RT011: path "/*\\.html$" has a "\\" that looks like a regular expression, patterns only treat "*" and a ` +
+			`"$" at the end specially and match it as it is`,
+		`line 11: warning RT011: path "/*(" has a "(" that looks like a regular expression, patterns only treat "*" and a "$" at the ` +
+			`end specially and match it as it is`,
+		`line 12: error RT007: crawl-delay "1.5" is not a whole number of seconds`,
+		`line 13: error RT007: crawl-delay "-1" is not a whole number of seconds`,
+		`line 14: warning RT008: "host" is not supported by this package and ignored by most crawlers`,
+		`line 15: error RT009: the line is not a comment and has no ":" between a key and a value so it is ignored`,
+		`line 18: warning RT005: user agent "Googlebot" is already named on line 17`,
+		`line 21: warning RT005: user agent "*" is also part of the group on line 2, only this group is used for it`,
+		`line 23: error RT006: sitemap "/sitemap.xml" is not an absolute http(s) URL`,
+	}, findingStrings(findings))
+
+	assert.Equal(t, `use "Disallow: /cms/"`, findings[1].Fix)
+	assert.Equal(t, `use "*" for any characters and "$" at the end for the end of the URL`, findings[4].Fix)
+	assert.Equal(t, `use "Disallow: /*.html$"`, findings[5].Fix)
+	assert.Equal(t, `round it up to whole seconds, "Crawl-delay: 2"`, findings[7].Fix)
+	for _, finding := range findings {
+		assert.NotEmpty(t, finding.Fix)
+	}
+}
+
+func TestLint_shadowed(t *testing.T) {
+	findings, err := robotstxt.Lint(strings.NewReader(`User-agent: *
+Disallow: /cms/
+Disallow: /cms/pages/
+Disallow: /shop/
+Allow: /shop/cart/
+Disallow: /shop/cart/checkout/
+Disallow: /blog/
+Allow: /blog*
+Disallow: /blog/drafts/
+`))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"line 3: warning RT003: Disallow: /cms/pages/ is shadowed by Disallow: /cms/ on line 2, every path it " +
+		"matches is already decided the same way"}, findingStrings(findings))
+}
+
+func TestLint_unused_rules_random(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	rules := []string{
+		"Disallow: /", "Allow: /", "Disallow: /cms/", "Allow: /cms/", "Disallow: /cms/public/", "Allow: /cms/public/",
+		"Disallow: /cms/public/page", "Allow: /cms*", "Disallow: /*/public/", "Allow: /*.html$", "Disallow: /cms/public/index.html",
+		"Disallow: /pricing", "Allow: /pricing/", "Disallow: /pricing/admin/",
+	}
+	urls := []string{
+		"/", "/cms", "/cms/", "/cms/public/", "/cms/public/page", "/cms/public/page.html", "/cms/public/index.html", "/blog/public/",
+		"/pricing", "/pricing/", "/pricing/admin/", "/pricing/admin/index.html", "/index.html",
+	}
+	for round := 0; round < 500; round++ {
+		source := []string{"User-agent: *"}
+		for i := random.Intn(8); i >= 0; i-- {
+			source = append(source, rules[random.Intn(len(rules))])
+		}
+		findings, err := robotstxt.Lint(strings.NewReader(strings.Join(source, "\n")))
+		assert.Nil(t, err)
+
+		// Removing the rules that never make a difference does not change what is allowed.
+		used := append([]string{}, source...)
+		for _, finding := range findings {
+			if finding.Code == "RT003" || finding.Code == "RT004" {
+				used[finding.Line-1] = ""
+			}
+		}
+		before, err := robotstxt.New("https://www.dumpsters.com", strings.NewReader(strings.Join(source, "\n")))
+		assert.Nil(t, err)
+		after, err := robotstxt.New("https://www.dumpsters.com", strings.NewReader(strings.Join(used, "\n")))
+		assert.Nil(t, err)
+		assert.Equal(t, before.CanCrawlBatch("googlebot", urls), after.CanCrawlBatch("googlebot", urls), strings.Join(source, "\n"))
+	}
+}
+
+func TestLint_clean(t *testing.T) {
+	findings, err := robotstxt.Lint(strings.NewReader(`# Comments and blank lines are fine.
+User-agent: *
+Crawl-delay: 5
+Disallow: /cms/
+Allow: /cms/public/
+Disallow: /*?s=lightbox
+Disallow: /*.pdf$
+Disallow:
+
+User-agent: AdsBot-Google
+Allow: /
+
+Sitemap: https://www.dumpsters.com/sitemap.xml
+`))
+	assert.Nil(t, err)
+	assert.Empty(t, findings)
+
+	findings, err = robotstxt.Lint(getExampleRobotsTxt())
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		`line 27: error RT009: the line is not a comment and has no ":" between a key and a value so it is ignored`,
+		`line 28: error RT009: the line has no key before its ":" so it is ignored`,
+	}, findingStrings(findings))
+}
+
+func TestLint_size(t *testing.T) {
+	var builder strings.Builder
+	builder.WriteString("User-agent: *\n")
+	for i := 0; i < 1000; i++ {
+		fmt.Fprintf(&builder, "Disallow: /%04d/%s\n", i, strings.Repeat("a", 1000))
+	}
+	findings, err := robotstxt.Lint(strings.NewReader(builder.String()))
+	assert.Nil(t, err)
+	assert.Len(t, findings, 1)
+	assert.Equal(t, "RT010", findings[0].Code)
+	assert.Equal(t, robotstxt.SeverityError, findings[0].Severity)
+	// The header is 14 bytes and every rule 1017, the line that crosses 500 KiB is reported.
+	assert.Equal(t, 505, findings[0].Line)
+}
+
+func TestLint_invalid_encoding(t *testing.T) {
+	findings, err := robotstxt.Lint(strings.NewReader("User-agent: *\nDisallow: /\xff\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"line 2: error RT013: the line is not valid UTF-8 so the robots.txt can not be read"},
+		findingStrings(findings))
+}
+
+func TestLint_patterns(t *testing.T) {
+	findings, err := robotstxt.Lint(strings.NewReader(`User-agent: *
+Disallow: /(cms|blog)/
+Disallow: /cms/[0-9]+
+Disallow: /^cms
+Disallow: /café
+Disallow: /a"b/*{x}
+Allow: /search?q={x}
+Allow: /*.pdf$
+`))
+	assert.Nil(t, err)
+	var codes []string
+	for _, finding := range findings {
+		codes = append(codes, fmt.Sprintf("%d %s", finding.Line, finding.Code))
+	}
+	assert.Equal(t, []string{"2 RT011", "2 RT012", "3 RT011", "4 RT011", "4 RT012", "5 RT012", "6 RT011", "6 RT012", "7 RT011"}, codes)
+	assert.Equal(t, `use "Disallow: /%5Ecms"`, findings[4].Fix)
+	assert.Equal(t, `use "Disallow: /caf%C3%A9"`, findings[5].Fix)
+	// Only the path is escaped, what comes after a "*" can be part of the query.
+	assert.Equal(t, `use "Disallow: /a%22b/*{x}"`, findings[7].Fix)
+}
+
+func TestLintRules(t *testing.T) {
+	codes := make(map[string]bool)
+	for i, lintRule := range robotstxt.LintRules {
+		assert.False(t, codes[lintRule.Code], lintRule.Code)
+		codes[lintRule.Code] = true
+		assert.Equal(t, fmt.Sprintf("RT%03d", i+1), lintRule.Code)
+		assert.NotEmpty(t, lintRule.Summary)
+	}
+}
+
+func findingStrings(findings []robotstxt.Finding) []string {
+	var strings []string
+	for _, finding := range findings {
+		strings = append(strings, finding.String())
+	}
+	return strings
+}
+
+func ExampleLint() {
+	findings, _ := robotstxt.Lint(strings.NewReader("Disallow: /cms/\nUser-agent: *\nDisallow: cms/\n"))
+	for _, finding := range findings {
+		fmt.Println(finding)
+		fmt.Println("\tfix:", finding.Fix)
+	}
+	// Output:
+	// line 1: warning RT001: Disallow before any User-agent is ignored
+	// 	fix: add a User-agent line above it
+	// line 3: error RT002: path "cms/" does not start with "/" or "*" so it never matches
+	// 	fix: use "Disallow: /cms/"
+}
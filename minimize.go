@@ -0,0 +1,173 @@
+package robotstxt
+
+import (
+	"sort"
+)
+
+/*
+Equivalent reports whether two robots.txt files allow and disallow exactly the same paths for the robots (user-agents). Every path
+is compared, not a sample of them, so files with rules in another order, with redundant rules, or with patterns that are written
+differently are still equivalent:
+
+	a: User-agent: *            b: User-agent: *
+	   Disallow: /cms/             Disallow: /cms/*
+	   Disallow: /cms/pages/       Allow: /*.pdf$
+	                               Disallow: /cms/*.pdf$
+
+	robotstxt.Equivalent(a, b, []string{"googlebot"}) // true, nil
+
+The robots that are named in either robots.txt and any other robot are compared when no agents are given. Crawl delays and sitemaps
+are not compared, see Diff for those. ErrTooComplex is returned for directives that have more ways to match a path than there is
+time to compare.
+*/
+func Equivalent(a, b *RobotsTxt, agents []string) (bool, error) {
+	if len(agents) == 0 {
+		agents = diffAgents(a, b)
+	}
+	for _, agent := range agents {
+		equivalent, err := equivalentRules(a.agentRules(agent), b.agentRules(agent))
+		if err != nil || !equivalent {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+/*
+Minimize returns a RobotsTxt without the allow and disallow directives that never decide whether a path can be crawled, it is
+Equivalent to the RobotsTxt for every robot:
+
+	User-agent: *                         User-agent: *
+	Disallow: /cms/                       Disallow: /cms/
+	Disallow: /cms/pages/           ->    Allow: /cms/public/
+	Allow: /cms/public/
+	Disallow: /cms/                       User-agent: googlebot
+	                                      Disallow:
+	User-agent: googlebot
+	Allow: /
+
+The directives of a group that no robot follows are removed as well, which is a group whose robots are all part of a later group.
+Everything else, the groups, crawl delays, sitemaps, and comments, stays the same. ErrTooComplex is returned for a group with
+directives that have more ways to match a path than there is time to compare.
+*/
+func Minimize(robotsTxt *RobotsTxt) (*RobotsTxt, error) {
+	minimized := &RobotsTxt{groups: robotsTxt.Groups(), sitemaps: robotsTxt.Sitemaps(), url: robotsTxt.url, comment: robotsTxt.comment}
+	followed := make(map[string]int) // The group every robot follows, the last one that names it.
+	for i, group := range minimized.groups {
+		for _, agent := range group.Agents {
+			followed[agent] = i
+		}
+	}
+	for i := range minimized.groups {
+		minimized.groups[i].Rules = nil
+	}
+	for _, i := range followed {
+		rules, err := minimizeRules(robotsTxt.groups[i].Rules)
+		if err != nil {
+			return &RobotsTxt{}, err
+		}
+		minimized.groups[i].Rules = rules
+	}
+
+	minimized.robots = make(map[string]robot, len(robotsTxt.robots))
+	for name, robot := range robotsTxt.robots {
+		if i, exists := followed[name]; exists {
+			robot.allowed, robot.disallowed = nil, nil
+			for _, rule := range minimized.groups[i].Rules {
+				if rule.Allow {
+					robot.allowed = append(robot.allowed, rule.Path)
+				} else {
+					robot.disallowed = append(robot.disallowed, rule.Path)
+				}
+			}
+			robot.index = newRuleIndex(robot.allowed, robot.disallowed)
+		}
+		minimized.robots[name] = robot
+	}
+	return minimized, nil
+}
+
+// minimizeRules removes the directives that can be removed without changing what the rest of them allow, later duplicates are
+// removed before earlier ones.
+func minimizeRules(rules []Rule) ([]Rule, error) {
+	var unique []Rule
+	for i, rule := range rules {
+		duplicate := false
+		for _, other := range rules[:i] {
+			duplicate = duplicate || other == rule
+		}
+		if !duplicate {
+			unique = append(unique, rule)
+		}
+	}
+
+	// The states of one automaton are witnesses of every way the directives can match a path, so a directive can be removed when
+	// every witness it matches is still decided the same way without it.
+	type witness struct {
+		matches []int
+		allowed bool
+	}
+	var witnesses []witness
+	matchedBy := make([][]int, len(unique)) // The witnesses every directive matches.
+	automaton := newAutomaton(unique)
+	err := automaton.explore(automaton.start(), "/", func(state *automatonState, path string) bool {
+		matches := automaton.matches(state)
+		allowed, _ := decide(unique, matches)
+		for _, rule := range matches {
+			matchedBy[rule] = append(matchedBy[rule], len(witnesses))
+		}
+		witnesses = append(witnesses, witness{matches: matches, allowed: allowed})
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Removing a directive can make another one useless, i.e. an allow that only won against the disallow that was removed.
+	removed := make([]bool, len(unique))
+	for changed := true; changed; {
+		changed = false
+		for i := len(unique) - 1; i >= 0; i-- {
+			redundant := !removed[i]
+			for j := 0; j < len(matchedBy[i]) && redundant; j++ {
+				witness := witnesses[matchedBy[i][j]]
+				var matches []int
+				for _, rule := range witness.matches {
+					if rule != i && !removed[rule] {
+						matches = append(matches, rule)
+					}
+				}
+				allowed, _ := decide(unique, matches)
+				redundant = allowed == witness.allowed
+			}
+			if redundant {
+				removed[i] = true
+				changed = true
+			}
+		}
+	}
+
+	var kept []Rule
+	for i, rule := range unique {
+		if !removed[i] {
+			kept = append(kept, rule)
+		}
+	}
+	return kept, nil
+}
+
+// equivalentRules reports whether two groups of directives decide every path the same way.
+func equivalentRules(a, b []Rule) (bool, error) {
+	equivalent := true
+	rules := append(append([]Rule{}, a...), b...)
+	automaton := newAutomaton(rules)
+	err := automaton.explore(automaton.start(), "/", func(state *automatonState, path string) bool {
+		matches := automaton.matches(state)
+		split := sort.SearchInts(matches, len(a))
+		aAllowed, _ := decide(rules, matches[:split])
+		bAllowed, _ := decide(rules, matches[split:])
+		equivalent = aAllowed == bAllowed
+		return equivalent
+	})
+	return equivalent && err == nil, err
+}
@@ -0,0 +1,167 @@
+package robotstxt_test
+
+import (
+	"fmt"
+	"github.com/itmayziii/robotstxt/v2"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEquivalent(t *testing.T) {
+	a := newRobotsTxt(t, "User-agent: *\nDisallow: /cms/\nDisallow: /cms/pages/\n\nUser-agent: googlebot\nAllow: /\n")
+	tests := []struct {
+		source     string
+		agents     []string
+		equivalent bool
+	}{
+		{source: "User-agent: *\nDisallow: /cms/\n\nUser-agent: googlebot\nDisallow:\n", equivalent: true},
+		{source: "User-agent: *\nDisallow: /cms/*\nAllow: /*.pdf$\nDisallow: /cms/*.pdf$\n\nUser-agent: googlebot\nDisallow:\n", equivalent: true},
+		{source: "User-agent: *\nDisallow: /cms/*\nAllow: /*.pdf$\n\nUser-agent: googlebot\nDisallow:\n", equivalent: false},
+		{source: "User-agent: *\nDisallow: /cms\n\nUser-agent: googlebot\nDisallow:\n", equivalent: false},
+		{source: "User-agent: *\nDisallow: /cms/\n", equivalent: false},
+		{source: "User-agent: *\nDisallow: /cms/\n", agents: []string{"bingbot", "*"}, equivalent: true},
+		{source: "User-agent: *\nDisallow: /cms/\nAllow: /*.html$\n\nUser-agent: googlebot\nDisallow:\n", equivalent: false},
+	}
+	for _, test := range tests {
+		b := newRobotsTxt(t, test.source)
+		assert.Equal(t, test.equivalent, equivalent(t, a, b, test.agents), test.source)
+		assert.Equal(t, test.equivalent, equivalent(t, b, a, test.agents), test.source)
+	}
+}
+
+func TestMinimize(t *testing.T) {
+	robotsTxt := newRobotsTxt(t, `User-agent: *
+Crawl-delay: 5
+Disallow: /cms/
+Disallow: /cms/pages/
+Allow: /cms/public/
+Disallow: /cms/
+Disallow: cms/
+Allow: /products/
+
+User-agent: googlebot
+Disallow: /private/
+
+User-agent: googlebot
+Allow: /
+
+Sitemap: https://www.dumpsters.com/sitemap.xml
+`)
+	minimized := minimize(t, robotsTxt)
+	assert.Equal(t, `User-agent: *
+Crawl-delay: 5
+Disallow: /cms/
+Allow: /cms/public/
+
+User-agent: googlebot
+Disallow:
+
+User-agent: googlebot
+Disallow:
+
+Sitemap: https://www.dumpsters.com/sitemap.xml
+`, minimized.String())
+	assert.True(t, equivalent(t, robotsTxt, minimized, nil))
+	assert.Equal(t, robotsTxt.CrawlDelay("bingbot"), minimized.CrawlDelay("bingbot"))
+
+	// The RobotsTxt that is minimized does not change.
+	assert.Len(t, robotsTxt.Groups()[0].Rules, 6)
+}
+
+func TestMinimize_random(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	rules := []string{
+		"Disallow: /", "Allow: /", "Disallow: /cms/", "Allow: /cms/", "Allow: /cms/public/", "Disallow: /*.pdf$", "Allow: /*?print",
+		"Disallow: /*/drafts/", "Allow: /cms/public/*.html$", "Disallow: /pricing", "Allow: /pricing/", "Disallow: *?s=",
+		"Disallow: /cms", "Allow: /cms$",
+	}
+	for round := 0; round < 50; round++ {
+		lines := []string{"User-agent: *"}
+		for i := random.Intn(8); i >= 0; i-- {
+			lines = append(lines, rules[random.Intn(len(rules))])
+		}
+		robotsTxt := newRobotsTxt(t, strings.Join(lines, "\n"))
+		minimized := minimize(t, robotsTxt)
+		assert.True(t, equivalent(t, robotsTxt, minimized, nil), strings.Join(lines, "\n"))
+
+		// Every directive that is left makes a difference.
+		kept := minimized.Groups()[0].Rules
+		for i := range kept {
+			builder := &robotstxt.Builder{}
+			group := builder.AddGroup("*")
+			for j, rule := range kept {
+				if j != i && rule.Allow {
+					group.Allow(rule.Path)
+				} else if j != i {
+					group.Disallow(rule.Path)
+				}
+			}
+			without, err := builder.Build("https://www.dumpsters.com")
+			assert.Nil(t, err)
+			assert.False(t, equivalent(t, minimized, without, nil), "%s without %s", strings.Join(lines, "\n"), kept[i])
+		}
+	}
+}
+
+// A large file is minimized with one automaton instead of one for every directive.
+func TestMinimize_large_files(t *testing.T) {
+	var redundant strings.Builder
+	for i := 2; i < 2000; i += 4 {
+		_, _ = fmt.Fprintf(&redundant, "Disallow: /products/category-%d/item-%d/reviews\n", i, i)
+	}
+	source := io.MultiReader(manyRulesRobotsTxt(2000), strings.NewReader(redundant.String()))
+	robotsTxt, err := robotstxt.New("https://www.dumpsters.com", source)
+	assert.Nil(t, err)
+
+	start := time.Now()
+	minimized := minimize(t, robotsTxt)
+	assert.True(t, time.Since(start) < 5*time.Second, time.Since(start).String())
+	assert.Len(t, minimized.Groups()[0].Rules, 2001)
+	for _, rule := range minimized.Groups()[0].Rules {
+		assert.False(t, strings.HasSuffix(rule.Path, "/reviews"), rule.Path)
+	}
+}
+
+// Directives with many stars have more ways to match a path than there is time to compare, both give up instead of hanging.
+func TestMinimize_too_complex(t *testing.T) {
+	robotsTxt := newRobotsTxt(t, hostileRobotsTxt(18))
+
+	start := time.Now()
+	_, err := robotstxt.Minimize(robotsTxt)
+	assert.Equal(t, robotstxt.ErrTooComplex, err)
+	equivalent, err := robotstxt.Equivalent(robotsTxt, newRobotsTxt(t, hostileRobotsTxt(10)), nil)
+	assert.Equal(t, robotstxt.ErrTooComplex, err)
+	assert.False(t, equivalent)
+	assert.True(t, time.Since(start) < 5*time.Second, time.Since(start).String())
+}
+
+func equivalent(t *testing.T, a, b *robotstxt.RobotsTxt, agents []string) bool {
+	equivalent, err := robotstxt.Equivalent(a, b, agents)
+	assert.Nil(t, err)
+	return equivalent
+}
+
+func minimize(t *testing.T, robotsTxt *robotstxt.RobotsTxt) *robotstxt.RobotsTxt {
+	minimized, err := robotstxt.Minimize(robotsTxt)
+	assert.Nil(t, err)
+	return minimized
+}
+
+func ExampleMinimize() {
+	robotsTxt, _ := robotstxt.New("https://www.dumpsters.com", strings.NewReader(`User-agent: *
+Disallow: /cms/
+Disallow: /cms/pages/
+Allow: /cms/public/
+Disallow: /cms/
+`))
+	minimized, _ := robotstxt.Minimize(robotsTxt)
+	fmt.Print(minimized)
+	// Output:
+	// User-agent: *
+	// Disallow: /cms/
+	// Allow: /cms/public/
+}
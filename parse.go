@@ -2,7 +2,7 @@ package robotstxt
 
 import (
 	"bufio"
-	"errors"
+	"fmt"
 	"io"
 	"strconv"
 	"strings"
@@ -28,8 +28,7 @@ func parse(url string, reader io.Reader) (*RobotsTxt, error) {
 		line := strings.TrimSpace(lineScanner.Text())
 
 		if validateUTF8(line) == false {
-			err := errors.New("invalid encoding detected on line " + strconv.Itoa(lineNumber) + ", all characters must be UTF-8 encoded")
-			return robotsTxt, err
+			return robotsTxt, &EncodingError{Line: lineNumber}
 		}
 
 		// Only process the text before any comment.
@@ -48,6 +47,12 @@ func parse(url string, reader io.Reader) (*RobotsTxt, error) {
 		separateKeyValue := strings.Split(line, ":")
 		key := strings.ToLower(strings.TrimSpace(separateKeyValue[0]))
 		value := strings.TrimSpace(strings.Join(separateKeyValue[1:], ":"))
+		// An empty allow or disallow does not match anything but it still ends the user agents of a group, "Disallow:" is how a lot
+		// of files say that a robot can crawl everything.
+		if value == "" && (key == "allow" || key == "disallow") {
+			endUserAgents = true
+			continue
+		}
 		// Another faulty key value pair.
 		if key == "" || value == "" {
 			continue
@@ -57,14 +62,20 @@ func parse(url string, reader io.Reader) (*RobotsTxt, error) {
 
 		switch key {
 		case "user-agent":
+			if endUserAgents || len(robotsTxt.groups) == 0 {
+				robotsTxt.groups = append(robotsTxt.groups, Group{})
+			}
 			if endUserAgents {
 				currentUserAgents = []string{}
 				endUserAgents = false
 			}
 			currentUserAgents = append(currentUserAgents, value)
 			robots[value] = robot{}
+			group := &robotsTxt.groups[len(robotsTxt.groups)-1]
+			group.Agents = append(group.Agents, value)
 			break
 		case "allow":
+			addRule(robotsTxt.groups, Rule{Allow: true, Path: value})
 			for _, userAgent := range currentUserAgents {
 				robot := robots[userAgent]
 				robot.allowed = append(robot.allowed, value)
@@ -73,6 +84,7 @@ func parse(url string, reader io.Reader) (*RobotsTxt, error) {
 			endUserAgents = true
 			break
 		case "disallow":
+			addRule(robotsTxt.groups, Rule{Path: value})
 			for _, userAgent := range currentUserAgents {
 				robot := robots[userAgent]
 				robot.disallowed = append(robot.disallowed, value)
@@ -87,19 +99,38 @@ func parse(url string, reader io.Reader) (*RobotsTxt, error) {
 		case "crawl-delay":
 			valueInt, err := strconv.Atoi(value)
 			if err != nil {
-				return robotsTxt, err
+				return robotsTxt, fmt.Errorf("invalid crawl-delay on line %d: %w", lineNumber, err)
 			}
 			for _, userAgent := range currentUserAgents {
 				robot := robots[userAgent]
 				robot.crawlDelay = time.Duration(valueInt) * time.Second
 				robots[userAgent] = robot
 			}
+			if len(robotsTxt.groups) > 0 {
+				robotsTxt.groups[len(robotsTxt.groups)-1].CrawlDelay = time.Duration(valueInt) * time.Second
+			}
 			endUserAgents = true
 			break
 		}
 	}
 
+	if err := lineScanner.Err(); err != nil {
+		return robotsTxt, fmt.Errorf("unable to read robots.txt: %w", err)
+	}
+
+	for userAgent, robot := range robots {
+		robot.index = newRuleIndex(robot.allowed, robot.disallowed)
+		robots[userAgent] = robot
+	}
+
 	robotsTxt.url = normalizedUrl
 	robotsTxt.robots = robots
 	return robotsTxt, nil
 }
+
+// addRule adds a rule to the group that is being parsed, rules before the first user agent do not belong to any group.
+func addRule(groups []Group, rule Rule) {
+	if len(groups) > 0 {
+		groups[len(groups)-1].Rules = append(groups[len(groups)-1].Rules, rule)
+	}
+}
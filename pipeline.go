@@ -0,0 +1,187 @@
+package robotstxt
+
+import (
+	"context"
+	"sync"
+)
+
+const (
+	defaultPipelineConcurrency = 16
+	defaultPipelineBuffer      = 64
+)
+
+// Result is the outcome of checking a single URL in a Pipeline.
+type Result struct {
+	// URL is the URL as it was received.
+	URL string
+
+	// Allowed reports whether the robot can crawl the URL.
+	Allowed bool
+
+	// Err is set when the URL could not be checked, i.e. it was not an absolute URL or its robots.txt could not be retrieved. These
+	// URLs are always blocked.
+	Err error
+}
+
+/*
+Pipeline checks a stream of absolute URLs against the robots.txt of their origins in bulk:
+
+	allowed, blocked := (&robotstxt.Pipeline{}).Filter(ctx, "dumpsterbot", urls)
+
+URLs are grouped by origin and every origin is handled by its own worker, the robots.txt of an origin is only retrieved once per
+worker and results for an origin come out in the same order as the URLs went in. No more than Concurrency origins are worked on at
+the same time.
+
+Both output channels are unbuffered beyond Buffer and have to be drained, a slow consumer slows down reading from the input channel.
+The zero value is ready to use.
+*/
+type Pipeline struct {
+	// Robots retrieves the robots.txt of each origin, a Registry is used when nil.
+	Robots Getter
+
+	// Concurrency is the number of origins worked on at the same time, 16 is used when zero.
+	Concurrency int
+
+	// Buffer is the number of URLs that can be queued up for each origin, 64 is used when zero.
+	Buffer int
+}
+
+type pipelineWorker struct {
+	origin string
+	queue  chan string
+
+	// pending is the number of URLs handed to the worker that it has not finished yet, it is guarded by the mutex of Filter.
+	pending int
+}
+
+// Filter reads URLs from in until it is closed or ctx is done and sends each of them to either allowed or blocked, both channels are
+// closed once every URL has been handled.
+func (pipeline *Pipeline) Filter(ctx context.Context, robotName string, in <-chan string) (<-chan Result, <-chan Result) {
+	allowed := make(chan Result)
+	blocked := make(chan Result)
+
+	robots := pipeline.Robots
+	if robots == nil {
+		robots = &Registry{Getter: &Fetcher{UserAgent: robotName}}
+	}
+	concurrency := pipeline.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultPipelineConcurrency
+	}
+	buffer := pipeline.Buffer
+	if buffer <= 0 {
+		buffer = defaultPipelineBuffer
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	workers := make(map[string]*pipelineWorker)
+	semaphore := make(chan struct{}, concurrency)
+
+	send := func(result Result) bool {
+		out := blocked
+		if result.Allowed {
+			out = allowed
+		}
+		select {
+		case out <- result:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	work := func(worker *pipelineWorker) {
+		defer wg.Done()
+		defer func() { <-semaphore }()
+
+		robotsTxt, getErr := robots.Get(ctx, worker.origin)
+		for {
+			var url string
+			select {
+			case url = <-worker.queue:
+			case <-ctx.Done():
+				return
+			}
+
+			result := Result{URL: url, Err: getErr}
+			if getErr == nil {
+				result.Allowed, result.Err = robotsTxt.CanCrawl(robotName, url)
+				result.Allowed = result.Allowed && result.Err == nil
+			}
+			if !send(result) {
+				return
+			}
+
+			// Only stop once nothing else was handed to this worker, the dispatcher starts a new worker for the origin afterwards.
+			mu.Lock()
+			worker.pending--
+			if worker.pending == 0 {
+				delete(workers, worker.origin)
+				mu.Unlock()
+				return
+			}
+			mu.Unlock()
+		}
+	}
+
+	go func() {
+		defer func() {
+			wg.Wait()
+			close(allowed)
+			close(blocked)
+		}()
+
+		for {
+			var url string
+			var ok bool
+			select {
+			case url, ok = <-in:
+				if !ok {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+
+			origin, err := normalizeUrl(url)
+			if err != nil {
+				if !send(Result{URL: url, Err: err}) {
+					return
+				}
+				continue
+			}
+
+			mu.Lock()
+			worker, exists := workers[origin]
+			if exists {
+				worker.pending++
+			}
+			mu.Unlock()
+			if !exists {
+				select {
+				case semaphore <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+				worker = &pipelineWorker{origin: origin, queue: make(chan string, buffer), pending: 1}
+				worker.queue <- url
+				mu.Lock()
+				workers[origin] = worker
+				mu.Unlock()
+				wg.Add(1)
+				go work(worker)
+				continue
+			}
+
+			// The worker can not go away while it has pending URLs, so this send only blocks while its queue is full.
+			select {
+			case worker.queue <- url:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return allowed, blocked
+}
@@ -0,0 +1,133 @@
+package robotstxt_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/itmayziii/robotstxt/v2"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPipeline_Filter(t *testing.T) {
+	var gets int32
+	pipeline := &robotstxt.Pipeline{Robots: countingGetter(&gets, "User-agent: *\nDisallow: /cms/\n"), Concurrency: 3, Buffer: 2}
+
+	in := make(chan string)
+	go func() {
+		defer close(in)
+		for i := 0; i < 100; i++ {
+			in <- fmt.Sprintf("https://www%d.dumpsters.com/products/%d", i%5, i)
+			in <- fmt.Sprintf("https://www%d.dumpsters.com/cms/%d", i%5, i)
+		}
+		in <- "/not/absolute"
+	}()
+
+	allowed, blocked := pipeline.Filter(context.Background(), "dumpsterbot", in)
+	allowedResults, blockedResults := drain(allowed, blocked)
+
+	assert.Len(t, allowedResults, 100)
+	assert.Len(t, blockedResults, 101)
+	for _, result := range allowedResults {
+		assert.Nil(t, result.Err)
+	}
+	errors := 0
+	for _, result := range blockedResults {
+		if result.Err != nil {
+			assert.Equal(t, "/not/absolute", result.URL)
+			errors++
+		}
+	}
+	assert.Equal(t, 1, errors)
+
+	// Results for an origin keep the order of the input.
+	for origin := 0; origin < 5; origin++ {
+		var numbers []int
+		for _, result := range allowedResults {
+			var host, number int
+			_, err := fmt.Sscanf(result.URL, "https://www%d.dumpsters.com/products/%d", &host, &number)
+			assert.Nil(t, err)
+			if host == origin {
+				numbers = append(numbers, number)
+			}
+		}
+		for i := 1; i < len(numbers); i++ {
+			assert.True(t, numbers[i-1] < numbers[i])
+		}
+	}
+
+	// Robots are retrieved once per worker, a worker only goes away once the queue of its origin runs dry.
+	assert.True(t, atomic.LoadInt32(&gets) >= 5)
+}
+
+func TestPipeline_Filter_cancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan string)
+	allowed, blocked := (&robotstxt.Pipeline{Robots: robotsTxtGetter("")}).Filter(ctx, "dumpsterbot", in)
+
+	in <- "https://www.dumpsters.com/"
+	result := <-allowed
+	assert.True(t, result.Allowed)
+
+	cancel()
+	_, _ = drain(allowed, blocked)
+}
+
+func BenchmarkPipeline_Filter(b *testing.B) {
+	for _, origins := range []int{1, 100, 10000} {
+		b.Run(fmt.Sprintf("%d_origins", origins), func(b *testing.B) {
+			pipeline := &robotstxt.Pipeline{Robots: &robotstxt.Registry{Getter: slowGetter(time.Millisecond)}}
+
+			in := make(chan string)
+			go func() {
+				defer close(in)
+				for i := 0; i < b.N; i++ {
+					in <- fmt.Sprintf("https://www%d.dumpsters.com/cms/%d", i%origins, i)
+				}
+			}()
+
+			b.ResetTimer()
+			allowed, blocked := pipeline.Filter(context.Background(), "googlebot", in)
+			_, _ = drain(allowed, blocked)
+		})
+	}
+}
+
+func drain(allowed, blocked <-chan robotstxt.Result) ([]robotstxt.Result, []robotstxt.Result) {
+	var allowedResults, blockedResults []robotstxt.Result
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for result := range allowed {
+			allowedResults = append(allowedResults, result)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for result := range blocked {
+			blockedResults = append(blockedResults, result)
+		}
+	}()
+	wg.Wait()
+	return allowedResults, blockedResults
+}
+
+func countingGetter(gets *int32, robotsTxt string) robotstxt.Getter {
+	getter := robotsTxtGetter(robotsTxt)
+	return robotstxt.GetterFunc(func(ctx context.Context, url string) (*robotstxt.RobotsTxt, error) {
+		atomic.AddInt32(gets, 1)
+		return getter.Get(ctx, url)
+	})
+}
+
+// slowGetter pretends every robots.txt takes a while to fetch.
+func slowGetter(latency time.Duration) robotstxt.Getter {
+	getter := fakeGetter(nil)
+	return robotstxt.GetterFunc(func(ctx context.Context, url string) (*robotstxt.RobotsTxt, error) {
+		time.Sleep(latency)
+		return getter.Get(ctx, url)
+	})
+}
@@ -0,0 +1,85 @@
+package robotstxt_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/itmayziii/robotstxt/v2"
+	"github.com/stretchr/testify/assert"
+	"net/url"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// The tests in this file are meant to be run with the race detector, go test -race -run Concurrent.
+
+func TestRobotsTxt_Concurrent_use(t *testing.T) {
+	robotsTxt, err := robotstxt.New("https://www.dumpsters.com", getExampleRobotsTxt())
+	assert.Nil(t, err)
+
+	paths := []string{"/cms/", "/cms", "/pricing?cart=full&s=lightbox", "/se/en", "/store/retail/online/frontend/", "/products/"}
+	expected := robotsTxt.CanCrawlBatch("googlebot", paths)
+
+	var wg sync.WaitGroup
+	for goroutine := 0; goroutine < 32; goroutine++ {
+		wg.Add(1)
+		go func(goroutine int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				path := paths[(goroutine+i)%len(paths)]
+				canCrawl, err := robotsTxt.CanCrawl("googlebot", path)
+				assert.Nil(t, err)
+				assert.Equal(t, expected[(goroutine+i)%len(paths)], canCrawl, path)
+
+				canCrawl, err = robotsTxt.CanCrawlURL("adsbot-google", &url.URL{Path: path})
+				assert.Nil(t, err)
+				assert.True(t, canCrawl)
+
+				assert.Equal(t, expected, robotsTxt.CanCrawlBatch("googlebot", paths))
+				assert.Equal(t, 5*time.Second, robotsTxt.CrawlDelay("googlebot"))
+				assert.Equal(t, "https://www.dumpsters.com:443", robotsTxt.URL())
+
+				// Callers are free to do whatever they want with the sitemaps they get back.
+				sitemaps := robotsTxt.Sitemaps()
+				assert.Len(t, sitemaps, 2)
+				sort.Strings(sitemaps)
+				sitemaps[0] = fmt.Sprintf("https://www.dumpsters.com/sitemap-%d.xml", goroutine)
+				_ = append(sitemaps[:1], "https://www.dumpsters.com/sitemap-extra.xml")
+			}
+		}(goroutine)
+	}
+	wg.Wait()
+
+	assert.Equal(t, []string{"https://www.dumpsters.com/sitemap.xml", "https://www.dumpsters.com/sitemap-launch-index.xml"}, robotsTxt.Sitemaps())
+}
+
+func TestRegistry_Concurrent_use(t *testing.T) {
+	registry := &robotstxt.Registry{Getter: fakeGetter(nil), MaxEntries: 3}
+	hosts := []string{"www.dumpsters.com", "www.budgetdumpster.com", "www.dumpsters.net", "www.rolloff.com"}
+
+	var wg sync.WaitGroup
+	for goroutine := 0; goroutine < 16; goroutine++ {
+		wg.Add(1)
+		go func(goroutine int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				host := hosts[(goroutine+i)%len(hosts)]
+				canCrawl, err := registry.CanCrawl(context.Background(), "googlebot", "https://"+host+"/cms/pages")
+				assert.Nil(t, err)
+				assert.Equal(t, host != "www.dumpsters.com", canCrawl, host)
+
+				robotsTxt, err := registry.Get(context.Background(), "https://"+host)
+				assert.Nil(t, err)
+				_ = robotsTxt.Sitemaps()
+				_ = robotsTxt.CrawlDelay("googlebot")
+				if i%10 == 0 {
+					registry.Remove("https://" + host)
+				}
+			}
+		}(goroutine)
+	}
+	wg.Wait()
+
+	assert.True(t, registry.Len() <= 3)
+}
@@ -0,0 +1,282 @@
+package robotstxt
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// maxRateInterval is the longest interval a RateServer accepts, a longer one is a bug or an attempt to block an origin.
+	maxRateInterval = 24 * time.Hour
+	// maxIdleRateConns is how many connections to a RateServer a RemoteRateStore keeps open for later reservations.
+	maxIdleRateConns = 8
+)
+
+/*
+RateStore hands out request slots per origin, it is what lets a Limiter share the crawl budget of a host with other Limiters. A
+process local Limiter uses a MemoryRateStore, a fleet of crawler processes can share a RateServer through RemoteRateStore instead:
+
+	// One process, or a separate service, runs the server.
+	listener, _ := net.Listen("tcp", "127.0.0.1:7070")
+	go (&robotstxt.RateServer{}).Serve(listener)
+
+	// Every crawler process uses it.
+	limiter := &robotstxt.Limiter{RobotName: "dumpsterbot", RateStore: &robotstxt.RemoteRateStore{Addr: "127.0.0.1:7070"}}
+*/
+type RateStore interface {
+	// Reserve atomically claims the next free slot for an origin and returns when it starts. A slot starts no earlier than
+	// interval after the start of the previously claimed slot for the same origin.
+	Reserve(ctx context.Context, origin string, interval time.Duration) (time.Time, error)
+}
+
+// MemoryRateStore is a RateStore for a single process, it is what a Limiter uses when no RateStore is configured. The zero value is
+// ready to use and a MemoryRateStore is safe for concurrent use.
+type MemoryRateStore struct {
+	mu           sync.Mutex
+	next         map[string]time.Time
+	reservations int
+}
+
+// Reserve claims the next free slot for an origin.
+func (memoryRateStore *MemoryRateStore) Reserve(ctx context.Context, origin string, interval time.Duration) (time.Time, error) {
+	memoryRateStore.mu.Lock()
+	defer memoryRateStore.mu.Unlock()
+
+	if memoryRateStore.next == nil {
+		memoryRateStore.next = make(map[string]time.Time)
+	}
+	now := timeNow()
+
+	// Origins whose slots are all in the past are forgotten every once in a while so the map does not grow forever.
+	memoryRateStore.reservations++
+	if memoryRateStore.reservations%1024 == 0 {
+		for otherOrigin, next := range memoryRateStore.next {
+			if !next.After(now) {
+				delete(memoryRateStore.next, otherOrigin)
+			}
+		}
+	}
+
+	at := now
+	if next := memoryRateStore.next[origin]; next.After(at) {
+		at = next
+	}
+	memoryRateStore.next[origin] = at.Add(interval)
+	return at, nil
+}
+
+/*
+RateServer serves a RateStore over TCP so RemoteRateStores in other processes share its slots. The protocol is line based, a
+request is "RESERVE <origin> <interval in nanoseconds>" and the response is either "OK <wait in nanoseconds>" or "ERR <message>".
+Responses carry how long to wait instead of a point in time so the clocks of the processes do not need to agree. An interval that
+is negative or longer than a day is rejected.
+
+A RateServer does not authenticate its clients, anyone who can connect can claim slots and delay every crawler that shares it. Only
+listen on loopback or a trusted private network.
+*/
+type RateServer struct {
+	// Store hands out the slots, a MemoryRateStore is used when nil.
+	Store RateStore
+
+	once  sync.Once
+	store RateStore
+}
+
+// Serve accepts connections on the listener until it is closed.
+func (rateServer *RateServer) Serve(listener net.Listener) error {
+	rateServer.once.Do(func() {
+		rateServer.store = rateServer.Store
+		if rateServer.store == nil {
+			rateServer.store = &MemoryRateStore{}
+		}
+	})
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+		go rateServer.serveConn(conn)
+	}
+}
+
+func (rateServer *RateServer) serveConn(conn net.Conn) {
+	defer conn.Close()
+
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		response := ""
+		if len(fields) != 3 || fields[0] != "RESERVE" {
+			response = "ERR invalid request"
+		} else if interval, err := strconv.ParseInt(fields[2], 10, 64); err != nil || !validRateInterval(time.Duration(interval)) {
+			response = "ERR invalid interval"
+		} else if at, err := rateServer.store.Reserve(context.Background(), fields[1], time.Duration(interval)); err != nil {
+			response = "ERR " + strings.Replace(err.Error(), "\n", " ", -1)
+		} else {
+			response = "OK " + strconv.FormatInt(int64(at.Sub(timeNow())), 10)
+		}
+
+		_, err := conn.Write([]byte(response + "\n"))
+		if err != nil {
+			return
+		}
+	}
+}
+
+func validRateInterval(interval time.Duration) bool {
+	return interval >= 0 && interval <= maxRateInterval
+}
+
+/*
+RemoteRateStore is a RateStore that claims its slots from a RateServer. Connections are kept open and reused for later reservations,
+Close closes the ones that are idle. The zero value is not usable, Addr must be set, and a RemoteRateStore is safe for concurrent
+use.
+*/
+type RemoteRateStore struct {
+	// Addr is the TCP address of the RateServer, i.e. "127.0.0.1:7070".
+	Addr string
+
+	mu   sync.Mutex
+	idle []*rateConn
+}
+
+type rateConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+// Reserve claims the next free slot for an origin from the RateServer. It returns as soon as ctx is done, even while it is waiting
+// for the RateServer to respond.
+func (remoteRateStore *RemoteRateStore) Reserve(ctx context.Context, origin string, interval time.Duration) (time.Time, error) {
+	if strings.ContainsAny(origin, " \t\r\n") {
+		return time.Time{}, errors.New("invalid origin " + strconv.Quote(origin))
+	}
+	if !validRateInterval(interval) {
+		return time.Time{}, errors.New("invalid interval " + interval.String())
+	}
+	request := "RESERVE " + origin + " " + strconv.FormatInt(int64(interval), 10) + "\n"
+
+	conn, reused, err := remoteRateStore.conn(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	response, err := remoteRateStore.roundTrip(ctx, conn, request)
+	if err == io.EOF && reused && ctx.Err() == nil {
+		// The RateServer closed the idle connection before it read the request, i.e. because it restarted.
+		_ = conn.Close()
+		conn, err = remoteRateStore.dial(ctx)
+		if err != nil {
+			return time.Time{}, err
+		}
+		response, err = remoteRateStore.roundTrip(ctx, conn, request)
+	}
+	if err != nil {
+		_ = conn.Close()
+		if ctx.Err() != nil {
+			return time.Time{}, ctx.Err()
+		}
+		return time.Time{}, err
+	}
+	remoteRateStore.release(conn)
+
+	response = strings.TrimSpace(response)
+	if strings.HasPrefix(response, "ERR ") {
+		return time.Time{}, errors.New("rate server: " + strings.TrimPrefix(response, "ERR "))
+	}
+	if !strings.HasPrefix(response, "OK ") {
+		return time.Time{}, errors.New("rate server: unexpected response " + strconv.Quote(response))
+	}
+	wait, err := strconv.ParseInt(strings.TrimPrefix(response, "OK "), 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	// Waiting from when the response arrived errs on the side of being late rather than early.
+	return timeNow().Add(time.Duration(wait)), nil
+}
+
+// Close closes the idle connections to the RateServer, connections that are in use are closed once their reservation is done.
+func (remoteRateStore *RemoteRateStore) Close() error {
+	remoteRateStore.mu.Lock()
+	idle := remoteRateStore.idle
+	remoteRateStore.idle = nil
+	remoteRateStore.mu.Unlock()
+
+	var err error
+	for _, conn := range idle {
+		if closeErr := conn.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// conn returns an idle connection, or a new one when there is none, and whether it was used before.
+func (remoteRateStore *RemoteRateStore) conn(ctx context.Context) (*rateConn, bool, error) {
+	remoteRateStore.mu.Lock()
+	if n := len(remoteRateStore.idle); n > 0 {
+		conn := remoteRateStore.idle[n-1]
+		remoteRateStore.idle = remoteRateStore.idle[:n-1]
+		remoteRateStore.mu.Unlock()
+		return conn, true, nil
+	}
+	remoteRateStore.mu.Unlock()
+
+	conn, err := remoteRateStore.dial(ctx)
+	return conn, false, err
+}
+
+func (remoteRateStore *RemoteRateStore) dial(ctx context.Context) (*rateConn, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", remoteRateStore.Addr)
+	if err != nil {
+		return nil, err
+	}
+	return &rateConn{Conn: conn, reader: bufio.NewReader(conn)}, nil
+}
+
+// release keeps a connection for later reservations, or closes it when enough connections are idle already.
+func (remoteRateStore *RemoteRateStore) release(conn *rateConn) {
+	remoteRateStore.mu.Lock()
+	defer remoteRateStore.mu.Unlock()
+	if len(remoteRateStore.idle) >= maxIdleRateConns {
+		_ = conn.Close()
+		return
+	}
+	remoteRateStore.idle = append(remoteRateStore.idle, conn)
+}
+
+// roundTrip sends a request and reads its response, a done ctx interrupts both by moving the deadline of the connection into the
+// past. A connection that returned an error must not be reused.
+func (remoteRateStore *RemoteRateStore) roundTrip(ctx context.Context, conn *rateConn, request string) (string, error) {
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return "", err
+	}
+
+	stop, stopped := make(chan struct{}), make(chan struct{})
+	defer func() {
+		close(stop)
+		<-stopped
+	}()
+	go func() {
+		defer close(stopped)
+		select {
+		case <-ctx.Done():
+			_ = conn.SetDeadline(time.Unix(1, 0))
+		case <-stop:
+		}
+	}()
+
+	if _, err := io.WriteString(conn, request); err != nil {
+		return "", err
+	}
+	return conn.reader.ReadString('\n')
+}
@@ -0,0 +1,213 @@
+package robotstxt_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"github.com/itmayziii/robotstxt/v2"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const rateStoreHelperEnv = "ROBOTSTXT_RATE_SERVER"
+
+func TestMemoryRateStore_Reserve(t *testing.T) {
+	rateStore := &robotstxt.MemoryRateStore{}
+
+	first, err := rateStore.Reserve(context.Background(), "https://www.dumpsters.com:443", time.Second)
+	assert.Nil(t, err)
+	second, err := rateStore.Reserve(context.Background(), "https://www.dumpsters.com:443", time.Second)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Second, second.Sub(first))
+
+	other, err := rateStore.Reserve(context.Background(), "https://www.budgetdumpster.com:443", time.Second)
+	assert.Nil(t, err)
+	assert.True(t, other.Before(second))
+}
+
+func TestRemoteRateStore_Reserve(t *testing.T) {
+	addr, closeServer := startRateServer(t)
+	defer closeServer()
+
+	rateStore := &robotstxt.RemoteRateStore{Addr: addr}
+	first, err := rateStore.Reserve(context.Background(), "https://www.dumpsters.com:443", time.Second)
+	assert.Nil(t, err)
+	second, err := rateStore.Reserve(context.Background(), "https://www.dumpsters.com:443", time.Second)
+	assert.Nil(t, err)
+	assert.True(t, second.Sub(first) >= time.Second-10*time.Millisecond)
+
+	_, err = rateStore.Reserve(context.Background(), "https://www.dumpsters.com :443", time.Second)
+	assert.NotNil(t, err)
+}
+
+func TestRateServer_rejects_invalid_intervals(t *testing.T) {
+	addr, closeServer := startRateServer(t)
+	defer closeServer()
+
+	conn, err := net.Dial("tcp", addr)
+	assert.Nil(t, err)
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for _, interval := range []string{"-1", strconv.FormatInt(int64(48*time.Hour), 10), "1s"} {
+		_, err = fmt.Fprintf(conn, "RESERVE https://www.dumpsters.com:443 %s\n", interval)
+		assert.Nil(t, err)
+		response, err := reader.ReadString('\n')
+		assert.Nil(t, err)
+		assert.Equal(t, "ERR invalid interval\n", response, interval)
+	}
+
+	rateStore := &robotstxt.RemoteRateStore{Addr: addr}
+	_, err = rateStore.Reserve(context.Background(), "https://www.dumpsters.com:443", -time.Second)
+	assert.EqualError(t, err, "invalid interval -1s")
+}
+
+type countingListener struct {
+	net.Listener
+	accepts int32
+}
+
+func (countingListener *countingListener) Accept() (net.Conn, error) {
+	conn, err := countingListener.Listener.Accept()
+	if err == nil {
+		atomic.AddInt32(&countingListener.accepts, 1)
+	}
+	return conn, err
+}
+
+func TestRemoteRateStore_reuses_connections(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	counter := &countingListener{Listener: listener}
+	go func() {
+		_ = (&robotstxt.RateServer{}).Serve(counter)
+	}()
+	defer listener.Close()
+
+	rateStore := &robotstxt.RemoteRateStore{Addr: listener.Addr().String()}
+	defer rateStore.Close()
+	for i := 0; i < 3; i++ {
+		_, err := rateStore.Reserve(context.Background(), "https://www.dumpsters.com:443", time.Millisecond)
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&counter.accepts))
+}
+
+func TestRemoteRateStore_Reserve_returns_when_the_context_is_done(t *testing.T) {
+	// A server that accepts connections but never responds.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	rateStore := &robotstxt.RemoteRateStore{Addr: listener.Addr().String()}
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	start := time.Now()
+	_, err = rateStore.Reserve(ctx, "https://www.dumpsters.com:443", time.Second)
+	assert.Equal(t, context.Canceled, err)
+	assert.True(t, time.Since(start) < time.Second)
+}
+
+// Two processes each make requests to the same host through their own Limiter, together they must still respect the Crawl-delay.
+func TestRemoteRateStore_shared_between_processes(t *testing.T) {
+	addr, closeServer := startRateServer(t)
+	defer closeServer()
+
+	cmd := exec.Command(os.Args[0], "-test.run=TestRemoteRateStore_helper_process")
+	cmd.Env = append(os.Environ(), rateStoreHelperEnv+"="+addr)
+	stdout, err := cmd.StdoutPipe()
+	assert.Nil(t, err)
+	assert.Nil(t, cmd.Start())
+
+	var mu sync.Mutex
+	var requests []time.Time
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for _, request := range limitedRequests(t, addr, 5) {
+			mu.Lock()
+			requests = append(requests, request)
+			mu.Unlock()
+		}
+	}()
+
+	scanner := bufio.NewScanner(stdout)
+	childRequests := 0
+	for scanner.Scan() {
+		if !strings.HasPrefix(scanner.Text(), "request ") {
+			continue
+		}
+		unixNano, err := strconv.ParseInt(strings.TrimPrefix(scanner.Text(), "request "), 10, 64)
+		assert.Nil(t, err)
+		mu.Lock()
+		requests = append(requests, time.Unix(0, unixNano))
+		mu.Unlock()
+		childRequests++
+	}
+	assert.Nil(t, cmd.Wait())
+	wg.Wait()
+
+	assert.Equal(t, 5, childRequests)
+	assert.Len(t, requests, 10)
+	sort.Slice(requests, func(i, j int) bool { return requests[i].Before(requests[j]) })
+	for i := 1; i < len(requests); i++ {
+		// Without a shared budget the two processes would make requests at roughly the same time, the margin is there for timers that
+		// fire late on a busy machine.
+		spacing := requests[i].Sub(requests[i-1])
+		assert.True(t, spacing >= 25*time.Millisecond, "requests %d and %d were only %s apart", i-1, i, spacing)
+	}
+}
+
+// TestRemoteRateStore_helper_process is not a real test, it is the second process of TestRemoteRateStore_shared_between_processes.
+func TestRemoteRateStore_helper_process(t *testing.T) {
+	addr := os.Getenv(rateStoreHelperEnv)
+	if addr == "" {
+		return
+	}
+	for _, request := range limitedRequests(t, addr, 5) {
+		fmt.Printf("request %d\n", request.UnixNano())
+	}
+}
+
+// limitedRequests pretends to make requests to a host with a 50ms Crawl-delay and returns when each of them was made.
+func limitedRequests(t *testing.T, addr string, count int) []time.Time {
+	limiter := &robotstxt.Limiter{
+		Robots:    robotsTxtGetter("User-agent: *\nCrawl-delay: 1\n"),
+		MaxDelay:  50 * time.Millisecond,
+		RateStore: &robotstxt.RemoteRateStore{Addr: addr},
+	}
+
+	var requests []time.Time
+	for i := 0; i < count; i++ {
+		assert.Nil(t, limiter.Wait(context.Background(), "https://www.dumpsters.com/"))
+		requests = append(requests, time.Now())
+	}
+	return requests
+}
+
+func startRateServer(t *testing.T) (string, func()) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	go func() {
+		_ = (&robotstxt.RateServer{}).Serve(listener)
+	}()
+	return listener.Addr().String(), func() { _ = listener.Close() }
+}
@@ -0,0 +1,257 @@
+package robotstxt
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultRegistryTTL is how long a Registry keeps a RobotsTxt before getting it again.
+	defaultRegistryTTL = 24 * time.Hour
+	// defaultRegistryErrorTTL is how long a Registry keeps an error before trying again.
+	defaultRegistryErrorTTL = time.Minute
+)
+
+// Getter retrieves the RobotsTxt for the origin of a URL, Fetcher, Cache, Registry, and Watcher are all Getters.
+type Getter interface {
+	Get(ctx context.Context, url string) (*RobotsTxt, error)
+}
+
+var (
+	_ Getter = (*Fetcher)(nil)
+	_ Getter = (*Cache)(nil)
+	_ Getter = (*Registry)(nil)
+	_ Getter = (*Watcher)(nil)
+)
+
+// GetterFunc is an adapter to allow the use of ordinary functions as a Getter.
+type GetterFunc func(ctx context.Context, url string) (*RobotsTxt, error)
+
+// Get calls getterFunc(ctx, url).
+func (getterFunc GetterFunc) Get(ctx context.Context, url string) (*RobotsTxt, error) {
+	return getterFunc(ctx, url)
+}
+
+/*
+Registry answers questions about any absolute URL by routing it to the RobotsTxt of its origin, a RobotsTxt on its own only covers
+a single origin. The RobotsTxt for an origin is retrieved through Getter the first time it is needed and kept in memory until it is
+older than TTL or it gets evicted. An error from Getter is kept for ErrorTTL so an origin that can not be retrieved is not asked
+again on every lookup, an error caused by the context of the caller is not kept.
+
+The least recently used origins are evicted once there are more than MaxEntries origins or the estimated memory of every RobotsTxt
+goes over MaxBytes, there is no limit when both are zero. The errors that are kept do not count toward either, expired errors are
+dropped whenever a new one is kept and no more than MaxEntries errors are kept at a time.
+
+The zero value is ready to use and a Registry is safe for concurrent use.
+*/
+type Registry struct {
+	// Getter retrieves the RobotsTxt for origins that are not in the registry, a zero Fetcher is used when nil.
+	Getter Getter
+
+	// TTL is how long a RobotsTxt is used before it is retrieved again, 24 hours is used when zero.
+	TTL time.Duration
+
+	// ErrorTTL is how long an error from Getter is returned before the origin is retrieved again, 1 minute is used when zero.
+	ErrorTTL time.Duration
+
+	// MaxEntries is the maximum number of origins to keep, there is no limit when zero.
+	MaxEntries int
+
+	// MaxBytes is the maximum estimated memory used by every RobotsTxt combined, there is no limit when zero.
+	MaxBytes int64
+
+	mu       sync.Mutex
+	entries  map[string]*list.Element
+	lru      *list.List
+	bytes    int64
+	failures map[string]registryFailure
+	inFlight flightGroup
+}
+
+type registryEntry struct {
+	origin    string
+	robotsTxt *RobotsTxt
+	size      int64
+	expires   time.Time
+}
+
+// registryFailure is the error of the last time an origin could not be retrieved.
+type registryFailure struct {
+	err     error
+	expires time.Time
+}
+
+// Get returns the RobotsTxt for the origin of an absolute URL, it is retrieved through Getter when it is not in the registry.
+func (registry *Registry) Get(ctx context.Context, url string) (*RobotsTxt, error) {
+	origin, err := normalizeUrl(url)
+	if err != nil {
+		return &RobotsTxt{}, err
+	}
+
+	registry.mu.Lock()
+	registry.init()
+	if element, exists := registry.entries[origin]; exists {
+		entry := element.Value.(*registryEntry)
+		if timeNow().Before(entry.expires) {
+			registry.lru.MoveToFront(element)
+			registry.mu.Unlock()
+			return entry.robotsTxt, nil
+		}
+		registry.remove(element)
+	}
+
+	if failure, exists := registry.failures[origin]; exists {
+		if timeNow().Before(failure.expires) {
+			registry.mu.Unlock()
+			return &RobotsTxt{}, failure.err
+		}
+		delete(registry.failures, origin)
+	}
+	registry.mu.Unlock()
+
+	value, err := registry.inFlight.do(ctx, origin, func(ctx context.Context) (interface{}, error) {
+		robotsTxt, err := registry.getter().Get(ctx, origin)
+		if err != nil && ctx.Err() != nil {
+			return robotsTxt, err
+		}
+
+		registry.mu.Lock()
+		defer registry.mu.Unlock()
+		if err != nil {
+			registry.addFailure(origin, err)
+			return robotsTxt, err
+		}
+		registry.add(origin, robotsTxt)
+		return robotsTxt, nil
+	})
+	robotsTxt, ok := value.(*RobotsTxt)
+	if err != nil || !ok {
+		return &RobotsTxt{}, err
+	}
+	return robotsTxt, nil
+}
+
+// CanCrawl determines whether or not a given robot (user-agent) is allowed to crawl an absolute URL on any origin.
+func (registry *Registry) CanCrawl(ctx context.Context, robotName, url string) (bool, error) {
+	robotsTxt, err := registry.Get(ctx, url)
+	if err != nil {
+		return false, err
+	}
+	return robotsTxt.CanCrawl(robotName, url)
+}
+
+// CrawlDelay is how long a robot will wait between accessing pages on the origin of an absolute URL.
+func (registry *Registry) CrawlDelay(ctx context.Context, robotName, url string) (time.Duration, error) {
+	robotsTxt, err := registry.Get(ctx, url)
+	if err != nil {
+		return 0, err
+	}
+	return robotsTxt.CrawlDelay(robotName), nil
+}
+
+// Remove forgets the RobotsTxt for the origin of a URL so it is retrieved again the next time it is needed.
+func (registry *Registry) Remove(url string) {
+	origin, err := normalizeUrl(url)
+	if err != nil {
+		return
+	}
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	if element, exists := registry.entries[origin]; exists {
+		registry.remove(element)
+	}
+	delete(registry.failures, origin)
+}
+
+// Len is the number of origins in the registry.
+func (registry *Registry) Len() int {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	return len(registry.entries)
+}
+
+// Bytes is the estimated memory used by every RobotsTxt in the registry.
+func (registry *Registry) Bytes() int64 {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	return registry.bytes
+}
+
+func (registry *Registry) init() {
+	if registry.entries == nil {
+		registry.entries = make(map[string]*list.Element)
+		registry.lru = list.New()
+		registry.failures = make(map[string]registryFailure)
+	}
+}
+
+func (registry *Registry) getter() Getter {
+	if registry.Getter != nil {
+		return registry.Getter
+	}
+	return &Fetcher{}
+}
+
+func (registry *Registry) errorTTL() time.Duration {
+	if registry.ErrorTTL > 0 {
+		return registry.ErrorTTL
+	}
+	return defaultRegistryErrorTTL
+}
+
+// add inserts a RobotsTxt as the most recently used and evicts the least recently used until the registry fits its budget again,
+// the caller must hold the lock.
+func (registry *Registry) add(origin string, robotsTxt *RobotsTxt) {
+	if element, exists := registry.entries[origin]; exists {
+		registry.remove(element)
+	}
+
+	ttl := registry.TTL
+	if ttl <= 0 {
+		ttl = defaultRegistryTTL
+	}
+	entry := &registryEntry{origin: origin, robotsTxt: robotsTxt, size: robotsTxt.size(), expires: timeNow().Add(ttl)}
+	registry.entries[origin] = registry.lru.PushFront(entry)
+	registry.bytes += entry.size
+
+	for registry.lru.Len() > 0 && registry.overBudget() {
+		registry.remove(registry.lru.Back())
+	}
+}
+
+// addFailure keeps the error of an origin, it drops the errors that expired and the ones closest to expiring while there are more
+// than MaxEntries. The caller must hold the lock.
+func (registry *Registry) addFailure(origin string, err error) {
+	now := timeNow()
+	for failureOrigin, failure := range registry.failures {
+		if !now.Before(failure.expires) {
+			delete(registry.failures, failureOrigin)
+		}
+	}
+	registry.failures[origin] = registryFailure{err: err, expires: now.Add(registry.errorTTL())}
+
+	for registry.MaxEntries > 0 && len(registry.failures) > registry.MaxEntries {
+		first := origin
+		for failureOrigin, failure := range registry.failures {
+			if failure.expires.Before(registry.failures[first].expires) {
+				first = failureOrigin
+			}
+		}
+		delete(registry.failures, first)
+	}
+}
+
+func (registry *Registry) overBudget() bool {
+	return (registry.MaxEntries > 0 && registry.lru.Len() > registry.MaxEntries) ||
+		(registry.MaxBytes > 0 && registry.bytes > registry.MaxBytes)
+}
+
+// remove drops an entry from the registry, the caller must hold the lock.
+func (registry *Registry) remove(element *list.Element) {
+	entry := registry.lru.Remove(element).(*registryEntry)
+	delete(registry.entries, entry.origin)
+	registry.bytes -= entry.size
+}
@@ -0,0 +1,181 @@
+package robotstxt_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/itmayziii/robotstxt/v2"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRegistry_CanCrawl(t *testing.T) {
+	registry := &robotstxt.Registry{Getter: fakeGetter(nil)}
+
+	canCrawl, err := registry.CanCrawl(context.Background(), "googlebot", "https://www.dumpsters.com/cms/pages")
+	assert.Nil(t, err)
+	assert.False(t, canCrawl)
+
+	canCrawl, err = registry.CanCrawl(context.Background(), "googlebot", "https://www.budgetdumpster.com/cms/pages")
+	assert.Nil(t, err)
+	assert.True(t, canCrawl)
+
+	crawlDelay, err := registry.CrawlDelay(context.Background(), "googlebot", "https://www.dumpsters.com/")
+	assert.Nil(t, err)
+	assert.Equal(t, 5*time.Second, crawlDelay)
+
+	_, err = registry.CanCrawl(context.Background(), "googlebot", "/cms/pages")
+	assert.NotNil(t, err)
+}
+
+func TestRegistry_Get_only_gets_an_origin_once(t *testing.T) {
+	var gets int32
+	registry := &robotstxt.Registry{Getter: fakeGetter(&gets)}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := registry.CanCrawl(context.Background(), "googlebot", fmt.Sprintf("https://www.dumpsters.com/page/%d", i))
+			assert.Nil(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&gets))
+	assert.Equal(t, 1, registry.Len())
+}
+
+func TestRegistry_Get_keeps_errors_for_ErrorTTL(t *testing.T) {
+	var gets int32
+	registry := &robotstxt.Registry{
+		Getter: robotstxt.GetterFunc(func(ctx context.Context, url string) (*robotstxt.RobotsTxt, error) {
+			atomic.AddInt32(&gets, 1)
+			return nil, errors.New("unreachable")
+		}),
+		ErrorTTL: 50 * time.Millisecond,
+	}
+
+	for i := 0; i < 3; i++ {
+		_, err := registry.Get(context.Background(), "https://www.dumpsters.com")
+		assert.EqualError(t, err, "unreachable")
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&gets))
+	assert.Equal(t, 0, registry.Len())
+
+	time.Sleep(60 * time.Millisecond)
+	_, err := registry.Get(context.Background(), "https://www.dumpsters.com")
+	assert.EqualError(t, err, "unreachable")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&gets))
+
+	// Remove forgets the error as well.
+	registry.Remove("https://www.dumpsters.com")
+	_, err = registry.Get(context.Background(), "https://www.dumpsters.com")
+	assert.EqualError(t, err, "unreachable")
+	assert.Equal(t, int32(3), atomic.LoadInt32(&gets))
+}
+
+func TestRegistry_Get_keeps_errors_for_MaxEntries_origins(t *testing.T) {
+	gets := make(map[string]int)
+	registry := &robotstxt.Registry{
+		Getter: robotstxt.GetterFunc(func(ctx context.Context, url string) (*robotstxt.RobotsTxt, error) {
+			gets[url]++
+			return nil, errors.New("unreachable")
+		}),
+		MaxEntries: 2,
+	}
+
+	for _, url := range []string{"https://a.dumpsters.com", "https://b.dumpsters.com", "https://c.dumpsters.com"} {
+		_, err := registry.Get(context.Background(), url)
+		assert.EqualError(t, err, "unreachable")
+		time.Sleep(time.Millisecond)
+	}
+	for _, url := range []string{"https://b.dumpsters.com", "https://c.dumpsters.com", "https://a.dumpsters.com"} {
+		_, _ = registry.Get(context.Background(), url)
+	}
+	// The error of the oldest origin was dropped so it is the only one that was retrieved again.
+	assert.Equal(t, map[string]int{
+		"https://a.dumpsters.com:443": 2,
+		"https://b.dumpsters.com:443": 1,
+		"https://c.dumpsters.com:443": 1,
+	}, gets)
+}
+
+func TestRegistry_Get_does_not_keep_errors_of_a_canceled_context(t *testing.T) {
+	var gets int32
+	registry := &robotstxt.Registry{Getter: robotstxt.GetterFunc(func(ctx context.Context, url string) (*robotstxt.RobotsTxt, error) {
+		atomic.AddInt32(&gets, 1)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return robotstxt.New(url, strings.NewReader("User-agent: *\nDisallow: /cms/\n"))
+	})}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := registry.Get(ctx, "https://www.dumpsters.com")
+	assert.Equal(t, context.Canceled, err)
+
+	_, err = registry.Get(context.Background(), "https://www.dumpsters.com")
+	assert.Nil(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&gets))
+}
+
+func TestRegistry_evicts_least_recently_used_entries(t *testing.T) {
+	var gets int32
+	registry := &robotstxt.Registry{Getter: fakeGetter(&gets), MaxEntries: 2}
+
+	for _, url := range []string{"https://a.com", "https://b.com", "https://a.com", "https://c.com"} {
+		_, err := registry.Get(context.Background(), url)
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, 2, registry.Len())
+	assert.Equal(t, int32(3), atomic.LoadInt32(&gets))
+
+	// "b" was the least recently used so it is the one that had to be retrieved again.
+	_, err := registry.Get(context.Background(), "https://a.com")
+	assert.Nil(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&gets))
+	_, err = registry.Get(context.Background(), "https://b.com")
+	assert.Nil(t, err)
+	assert.Equal(t, int32(4), atomic.LoadInt32(&gets))
+}
+
+func TestRegistry_evicts_entries_over_the_memory_budget(t *testing.T) {
+	registry := &robotstxt.Registry{Getter: fakeGetter(nil)}
+	_, err := registry.Get(context.Background(), "https://www.dumpsters.com")
+	assert.Nil(t, err)
+	entrySize := registry.Bytes()
+	assert.True(t, entrySize > 0)
+
+	registry = &robotstxt.Registry{Getter: fakeGetter(nil), MaxBytes: 3*entrySize + entrySize/2}
+	for i := 0; i < 10; i++ {
+		_, err := registry.Get(context.Background(), fmt.Sprintf("https://www%d.dumpsters.com", i))
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, 3, registry.Len())
+	assert.True(t, registry.Bytes() <= registry.MaxBytes)
+
+	registry.Remove("https://www9.dumpsters.com")
+	assert.Equal(t, 2, registry.Len())
+}
+
+// fakeGetter pretends that only dumpsters.com hosts have a robots.txt.
+func fakeGetter(gets *int32) robotstxt.Getter {
+	return robotstxt.GetterFunc(func(ctx context.Context, url string) (*robotstxt.RobotsTxt, error) {
+		if gets != nil {
+			atomic.AddInt32(gets, 1)
+			// Give concurrent callers a chance to pile up.
+			time.Sleep(10 * time.Millisecond)
+		}
+		if !strings.Contains(url, "dumpsters.com") {
+			return robotstxt.New(url, strings.NewReader(""))
+		}
+		return robotstxt.New(url, getExampleRobotsTxt())
+	})
+}
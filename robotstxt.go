@@ -81,20 +81,14 @@ The following are examples of only looking at the top level for /robots.txt:
  https://www.dumpsters.com/robots.txt                 -> https://www.dumpsters.com/robots.txt
 */
 func NewFromURL(url string, getFn func(url string) (resp *http.Response, err error)) (*RobotsTxt, error) {
-	parsedUrl, err := netUrl.Parse(url)
+	robotsTxtUrl, err := robotsTxtURL(url)
 	if err != nil {
 		return &RobotsTxt{}, err
 	}
 
-	normalizedUrl := parsedUrl.Scheme + "://" + parsedUrl.Hostname()
-	port := parsedUrl.Port()
-	if port != "" {
-		normalizedUrl = parsedUrl.Scheme + "://" + parsedUrl.Hostname() + ":" + port
-	}
-
-	resp, err := getFn(normalizedUrl + "/robots.txt")
+	resp, err := getFn(robotsTxtUrl)
 	if err != nil {
-		return &RobotsTxt{}, err
+		return &RobotsTxt{}, &FetchError{URL: robotsTxtUrl, Err: err}
 	}
 
 	robotsTxtBody, err := parseRobotsTxtBody(resp.Body)
@@ -113,65 +107,192 @@ func NewFromURL(url string, getFn func(url string) (resp *http.Response, err err
 // defined. Directives such as allow and disallow are not important for a robot (user-agent) to know about, they are implementation details,
 // instead a robot just needs to know if it is allowed to crawl a given path so this interface provides a "CanCrawl" method as opposed to giving you
 // direct access to allow and disallow.
+//
+// A RobotsTxt never changes after it is created, every method is safe to call from many goroutines at the same time and methods that
+// return slices return copies that the caller is free to modify.
 type RobotsTxt struct {
 	robots   map[string]robot
+	groups   []Group
 	sitemaps []string
 	url      string
+	comment  string
 }
 
 type robot struct {
 	disallowed []string
 	allowed    []string
 	crawlDelay time.Duration
+	index      *ruleIndex
+}
+
+// Group is a group of a robots.txt, the rules and crawl delay for one or more robots (user-agents).
+type Group struct {
+	// Agents are the robots (user-agents) the group is for, i.e. "googlebot" or "*".
+	Agents []string
+
+	// Rules are the allow and disallow directives of the group in the order they were defined.
+	Rules []Rule
+
+	// CrawlDelay is how long the robots wait between accessing pages, it is zero when the group does not define a Crawl-delay.
+	CrawlDelay time.Duration
+
+	// Comment is written above the group, it is only set for groups made by a Builder.
+	Comment string
+}
+
+// Rule is a single allow or disallow directive.
+type Rule struct {
+	// Allow is true for allow directives and false for disallow directives.
+	Allow bool
+
+	// Path is the path pattern of the directive, i.e. "/cms/" or "/*.pdf$".
+	Path string
+}
+
+// String returns the rule the way it is written in a robots.txt, i.e. "Disallow: /cms/".
+func (rule Rule) String() string {
+	if rule.Allow {
+		return "Allow: " + rule.Path
+	}
+	return "Disallow: " + rule.Path
 }
 
 // CanCrawl determines whether or not a given robot (user-agent) is allowed to crawl a URL based on allow and disallow directives in the robots.txt.
+// The URL is either a path or an absolute URL, see CanCrawlURL for what happens with absolute URLs of another origin.
 func (robotsTxt *RobotsTxt) CanCrawl(robotName, url string) (bool, error) {
-	robot, exists := findMatchingRobot(robotName, robotsTxt.robots)
-	if !exists {
-		return true, nil
+	parsedUrl, err := netUrl.Parse(url)
+	if err != nil {
+		return false, err
 	}
+	return robotsTxt.CanCrawlURL(robotName, parsedUrl)
+}
 
-	// Everything is allowed if nothing is disallowed.
-	if robot.disallowed == nil || len(robot.disallowed) == 0 {
-		return true, nil
+/*
+CanCrawlURL is CanCrawl for a URL that is already parsed. Directives only apply to the scheme, host, and port the robots.txt was
+created for, an absolute URL of any other origin is not crawlable and ErrOriginMismatch is returned. To check just the path of a URL
+against a robots.txt of another origin leave out the scheme and host:
+
+	robotsTxt.CanCrawlURL("googlebot", &url.URL{Path: otherUrl.Path, RawQuery: otherUrl.RawQuery})
+
+The fragment and user information of the URL are ignored, https://www.rfc-editor.org/rfc/rfc9309.html#section-2.2.2. Whenever an
+error is returned the URL is reported as not crawlable.
+*/
+func (robotsTxt *RobotsTxt) CanCrawlURL(robotName string, url *netUrl.URL) (bool, error) {
+	robot, exists := findMatchingRobot(robotName, robotsTxt.robots)
+	canCrawl, _, err := robotsTxt.canCrawl(robot, exists, url)
+	return canCrawl, err
+}
+
+// CanCrawlBatch is CanCrawl for many URLs at once, the robot is only looked up once for all of them. URLs are reported as not
+// crawlable when CanCrawl would return an error for them.
+func (robotsTxt *RobotsTxt) CanCrawlBatch(robotName string, urls []string) []bool {
+	results := make([]bool, len(urls))
+	robot, exists := findMatchingRobot(robotName, robotsTxt.robots)
+	for i, url := range urls {
+		parsedUrl, err := netUrl.Parse(url)
+		if err != nil {
+			continue
+		}
+		results[i], _, _ = robotsTxt.canCrawl(robot, exists, parsedUrl)
 	}
+	return results
+}
+
+// Explanation is why a robot can or can not crawl a URL, see Explain.
+type Explanation struct {
+	// CanCrawl is what CanCrawl returns for the URL.
+	CanCrawl bool
+
+	// Agent is the user agent of the group that applies to the robot, it is empty when no group applies.
+	Agent string
+
+	// Rule is the allow or disallow directive that decided, it is nil when no directive matches the URL.
+	Rule *Rule
+}
 
-	// URL provided must be able to be parsed.
+/*
+Explain is CanCrawl that also tells which group applies to the robot and which directive decided:
+
+	explanation, err := robotsTxt.Explain("googlebot", "/cms/pages")
+	// explanation.CanCrawl = false
+	// explanation.Agent = "*"
+	// explanation.Rule = &Rule{Allow: false, Path: "/cms/"}
+
+An allow directive that matches is the Rule even when nothing is disallowed.
+*/
+func (robotsTxt *RobotsTxt) Explain(robotName, url string) (Explanation, error) {
 	parsedUrl, err := netUrl.Parse(url)
 	if err != nil {
-		return true, err
+		return Explanation{}, err
 	}
+	err = robotsTxt.checkOrigin(parsedUrl)
+	if err != nil {
+		return Explanation{}, err
+	}
+
+	agent := matchingRobotName(robotName, robotsTxt.robots)
+	if agent == "" {
+		return Explanation{CanCrawl: true}, nil
+	}
+	allowed, disallowed := matchPath(robotsTxt.robots[agent], parsedUrl)
+	explanation := Explanation{CanCrawl: true, Agent: agent}
+	if disallowed != "" && len(allowed) < len(disallowed) {
+		explanation.CanCrawl = false
+		explanation.Rule = &Rule{Path: disallowed}
+	} else if allowed != "" {
+		explanation.Rule = &Rule{Allow: true, Path: allowed}
+	}
+	return explanation, nil
+}
+
+// canCrawl is CanCrawlURL that also returns the path of the disallow directive responsible for a URL not being crawlable.
+func (robotsTxt *RobotsTxt) canCrawl(robot robot, exists bool, url *netUrl.URL) (bool, string, error) {
+	err := robotsTxt.checkOrigin(url)
+	if err != nil {
+		return false, "", err
+	}
+
+	// Everything is allowed if nothing is disallowed.
+	if !exists || len(robot.disallowed) == 0 {
+		return true, "", nil
+	}
+
+	allowed, disallowed := matchPath(robot, url)
+	if disallowed == "" || len(allowed) >= len(disallowed) {
+		return true, "", nil
+	}
+	return false, disallowed, nil
+}
 
+// checkOrigin returns ErrOriginMismatch for an absolute URL of another origin than the robots.txt.
+func (robotsTxt *RobotsTxt) checkOrigin(url *netUrl.URL) error {
 	// Basically if the URL provided is a full URL with a schema then the robot URL must match completely.
 	// https://developers.google.com/search/reference/robots_txt#file-location--range-of-validity
-	if parsedUrl.IsAbs() {
-		normalizedUrl, err := normalizeUrl(parsedUrl.String())
-		if err != nil {
-			return true, err
-		}
-		if robotsTxt.url != normalizedUrl {
-			return true, errors.New("absolute URL provided but the robot URL did not match")
-		}
+	if !url.IsAbs() {
+		return nil
+	}
+	origin, err := urlOrigin(url)
+	if err != nil {
+		return err
 	}
+	if robotsTxt.url != origin {
+		return ErrOriginMismatch
+	}
+	return nil
+}
 
-	// Prepend a leading slash if the url provided does not have one, just one less thing we have to account for later on
-	normalizedPath := parsedUrl.RequestURI()
+// matchPath returns the most specific allow and disallow directives of the robot that match the path of a URL.
+func matchPath(robot robot, url *netUrl.URL) (string, string) {
+	// Prepend a leading slash if the url provided does not have one, just one less thing we have to account for later on. The
+	// request URI never has the fragment or user information in it.
+	normalizedPath := url.RequestURI()
 	if !strings.HasPrefix(normalizedPath, "/") {
 		normalizedPath = "/" + normalizedPath
 	}
 
 	// With allow and disallow directives, the most specific rule based on the length of the [path] entry will trump the less specific (shorter) rule.
 	// https://developers.google.com/search/reference/robots_txt#url-matching-based-on-path-values
-	disallowedLength, err := urlMatchLength(normalizedPath, robot.disallowed)
-	if err != nil {
-		return true, err
-	}
-	allowedLength, err := urlMatchLength(normalizedPath, robot.allowed)
-	if err != nil {
-		return true, err
-	}
-	return disallowedLength == 0 || allowedLength >= disallowedLength, nil
+	return robot.index.match(normalizedPath)
 }
 
 // CrawlDelay is how long a robot will wait between accessing pages on a site.
@@ -182,7 +303,28 @@ func (robotsTxt *RobotsTxt) CrawlDelay(robotName string) time.Duration {
 
 // Sitemaps returns the sitemaps that are defined in the robots.txt.
 func (robotsTxt *RobotsTxt) Sitemaps() []string {
-	return robotsTxt.sitemaps
+	if robotsTxt.sitemaps == nil {
+		return nil
+	}
+	sitemaps := make([]string, len(robotsTxt.sitemaps))
+	copy(sitemaps, robotsTxt.sitemaps)
+	return sitemaps
+}
+
+// Groups returns the groups of the robots.txt in the order they were defined. A robot that is part of more than one group only
+// follows the last one, see https://developers.google.com/search/reference/robots_txt#order-of-precedence-for-user-agents for how a
+// robot picks the group it follows.
+func (robotsTxt *RobotsTxt) Groups() []Group {
+	if robotsTxt.groups == nil {
+		return nil
+	}
+	groups := make([]Group, len(robotsTxt.groups))
+	for i, group := range robotsTxt.groups {
+		groups[i] = group
+		groups[i].Agents = append([]string(nil), group.Agents...)
+		groups[i].Rules = append([]Rule(nil), group.Rules...)
+	}
+	return groups
 }
 
 // URL is a getter that returns the URL a particular robots.txt file is associated with, i.e. https://www.dumpsters.com:443. The port is assumed from
@@ -191,6 +333,33 @@ func (robotsTxt *RobotsTxt) URL() string {
 	return robotsTxt.url
 }
 
+// size is a rough estimate of how much memory the RobotsTxt uses, it counts the strings it holds plus a fixed overhead for every
+// string, slice, and map entry.
+func (robotsTxt *RobotsTxt) size() int64 {
+	const overhead = 16
+	size := int64(len(robotsTxt.url) + len(robotsTxt.comment) + overhead)
+	for _, group := range robotsTxt.groups {
+		// The agents and paths are the same strings that the robots use.
+		size += int64((4 + len(group.Agents) + 2*len(group.Rules)) * overhead)
+	}
+	for _, sitemap := range robotsTxt.sitemaps {
+		size += int64(len(sitemap) + overhead)
+	}
+	for name, robot := range robotsTxt.robots {
+		size += int64(len(name) + 4*overhead)
+		for _, path := range robot.allowed {
+			size += int64(len(path) + overhead)
+		}
+		for _, path := range robot.disallowed {
+			size += int64(len(path) + overhead)
+		}
+		if robot.index != nil {
+			size += robot.index.size()
+		}
+	}
+	return size
+}
+
 func parseRobotsTxtBody(readCloser io.ReadCloser) (string, error) {
 	node, err := html.Parse(readCloser)
 	if err != nil {
@@ -0,0 +1,91 @@
+package robotstxttest
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+)
+
+// Suite is the results of one expectations file.
+type Suite struct {
+	// Name is what the suite is called in JUnit XML, usually the path of the expectations file.
+	Name string
+
+	Results []Result
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+/*
+WriteJUnit writes results as JUnit XML, which most CI systems show as test results. Every suite is a test suite and every expectation
+is a test case named after its line:
+
+	<testsuites>
+	  <testsuite name="robots_expectations.txt" tests="2" failures="1" errors="0">
+	    <testcase name="line 2: googlebot /products/* allow" classname="robots_expectations.txt"></testcase>
+	    <testcase name="line 3: * /cart deny" classname="robots_expectations.txt">
+	      <failure message="* can crawl /cart, no rule of the group of &#34;*&#34; matches">Keep carts private</failure>
+	    </testcase>
+	  </testsuite>
+	</testsuites>
+
+The comment of an expectation is the text of its failure. An expectation that could not be checked is an error instead of a failure.
+*/
+func WriteJUnit(writer io.Writer, suites ...Suite) error {
+	junitSuites := junitTestSuites{Suites: []junitTestSuite{}}
+	for _, suite := range suites {
+		junitSuites.Suites = append(junitSuites.Suites, newJUnitTestSuite(suite))
+	}
+
+	if _, err := io.WriteString(writer, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(junitSuites); err != nil {
+		return err
+	}
+	_, err := io.WriteString(writer, "\n")
+	return err
+}
+
+func newJUnitTestSuite(suite Suite) junitTestSuite {
+	junitSuite := junitTestSuite{Name: suite.Name, Tests: len(suite.Results), TestCases: []junitTestCase{}}
+	for _, result := range suite.Results {
+		name := "line " + strconv.Itoa(result.Expectation.Line) + ": " + result.Expectation.String()
+		testCase := junitTestCase{Name: name, ClassName: suite.Name}
+		switch {
+		case result.Err != nil:
+			junitSuite.Errors++
+			testCase.Error = &junitProblem{Message: result.Err.Error(), Text: result.Expectation.Comment}
+		case !result.Passed:
+			junitSuite.Failures++
+			testCase.Failure = &junitProblem{Message: result.Message, Text: result.Expectation.Comment}
+		}
+		junitSuite.TestCases = append(junitSuite.TestCases, testCase)
+	}
+	return junitSuite
+}
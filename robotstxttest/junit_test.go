@@ -0,0 +1,35 @@
+package robotstxttest_test
+
+import (
+	"bytes"
+	"github.com/itmayziii/robotstxt/v2"
+	"github.com/itmayziii/robotstxt/v2/robotstxttest"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestWriteJUnit(t *testing.T) {
+	results := robotstxttest.Run(newRobotsTxt(t), []robotstxttest.Expectation{
+		{Line: 1, Agent: "googlebot", URL: "/products/*", Allow: true},
+		{Line: 2, Agent: "*", URL: "/checkout", Comment: "Keep checkouts private"},
+		{Line: 3, Agent: "*", URL: "https://www.example.com/"},
+	})
+
+	var buffer bytes.Buffer
+	assert.Nil(t, robotstxttest.WriteJUnit(&buffer, robotstxttest.Suite{Name: "expectations.txt", Results: results},
+		robotstxttest.Suite{Name: "empty.txt"}))
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="expectations.txt" tests="3" failures="1" errors="1">
+    <testcase name="line 1: googlebot /products/* allow" classname="expectations.txt"></testcase>
+    <testcase name="line 2: * /checkout deny" classname="expectations.txt">
+      <failure message="* can crawl /checkout, no rule of the group of &#34;*&#34; matches">Keep checkouts private</failure>
+    </testcase>
+    <testcase name="line 3: * https://www.example.com/ deny" classname="expectations.txt">
+      <error message="`+robotstxt.ErrOriginMismatch.Error()+`"></error>
+    </testcase>
+  </testsuite>
+  <testsuite name="empty.txt" tests="0" failures="0" errors="0"></testsuite>
+</testsuites>
+`, buffer.String())
+}
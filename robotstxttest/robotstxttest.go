@@ -0,0 +1,250 @@
+/*
+Package robotstxttest checks a robots.txt against expectations, which pin down what robots (user-agents) must and must not be able
+to crawl so a change to the robots.txt that breaks them is caught before it is deployed.
+
+An expectations file has one expectation per line, the agent, the URL, and whether the agent is expected to be allowed or denied to
+crawl the URL, followed by an optional comment:
+
+	# The product pages bring in all of our traffic.
+	googlebot  /products/*  allow  # Googlebot must be able to crawl /products/*
+	*          /cart        deny   # Everything must be blocked from /cart
+	bingbot    https://www.dumpsters.com/checkout?step=1  deny
+
+Fields are separated by spaces or tabs and a comment starts with "#". A URL is a path or an absolute URL the same way as for
+robotstxt.RobotsTxt.CanCrawl, and a URL that ends with "*" is every path that starts with what comes before the "*", see
+robotstxt.RobotsTxt.SubtreeStatus. Blank lines and lines with only a comment are ignored.
+
+Check runs the expectations of a file from a Go test:
+
+	func TestRobotsTxt(t *testing.T) {
+		robotsTxt, err := robotstxt.NewFromFile("https://www.dumpsters.com", "robots.txt")
+		if err != nil {
+			t.Fatal(err)
+		}
+		robotstxttest.Check(t, robotsTxt, "robots_expectations.txt")
+	}
+
+The robotstxt test command runs them from CI and writes JUnit XML with -junit.
+*/
+package robotstxttest
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/itmayziii/robotstxt/v2"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Expectation is one line of an expectations file.
+type Expectation struct {
+	// Line is the line, counting from 1, the expectation is on.
+	Line int
+
+	// Agent is the robot (user-agent) the expectation is for, i.e. "googlebot" or "*".
+	Agent string
+
+	// URL is a path or an absolute URL, every path that starts with it when it ends with "*".
+	URL string
+
+	// Allow is whether the robot is expected to be able to crawl the URL.
+	Allow bool
+
+	// Comment is the comment at the end of the line without the "#", it is empty when there is none.
+	Comment string
+}
+
+// String returns the expectation the way it is written in an expectations file, without its comment.
+func (expectation Expectation) String() string {
+	verdict := "deny"
+	if expectation.Allow {
+		verdict = "allow"
+	}
+	return expectation.Agent + " " + expectation.URL + " " + verdict
+}
+
+// SyntaxError is returned when a line of an expectations file is not an expectation.
+type SyntaxError struct {
+	// Line is the line, counting from 1, that is not an expectation.
+	Line int
+
+	// Message is what is wrong with the line.
+	Message string
+}
+
+func (syntaxError *SyntaxError) Error() string {
+	return "line " + strconv.Itoa(syntaxError.Line) + ": " + syntaxError.Message
+}
+
+// Parse reads the expectations of an expectations file, a SyntaxError is returned for the first line that is not an expectation.
+func Parse(reader io.Reader) ([]Expectation, error) {
+	var expectations []Expectation
+	lineScanner := bufio.NewScanner(reader)
+	lineNumber := 0
+	for lineScanner.Scan() {
+		lineNumber++
+		line, comment := lineScanner.Text(), ""
+		if i := strings.Index(line, "#"); i != -1 {
+			line, comment = line[:i], strings.TrimSpace(line[i+1:])
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 3 {
+			message := fmt.Sprintf("expected an agent, a URL, and allow or deny but found %d fields", len(fields))
+			return nil, &SyntaxError{Line: lineNumber, Message: message}
+		}
+
+		expectation := Expectation{Line: lineNumber, Agent: fields[0], URL: fields[1], Comment: comment}
+		switch strings.ToLower(fields[2]) {
+		case "allow":
+			expectation.Allow = true
+		case "deny":
+			expectation.Allow = false
+		default:
+			return nil, &SyntaxError{Line: lineNumber, Message: fmt.Sprintf("expected allow or deny but found %q", fields[2])}
+		}
+		expectations = append(expectations, expectation)
+	}
+
+	if err := lineScanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read expectations: %w", err)
+	}
+	return expectations, nil
+}
+
+// ParseFile is Parse for the expectations file at a path.
+func ParseFile(path string) ([]Expectation, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return Parse(file)
+}
+
+// Result is whether a robots.txt meets an expectation, see Run.
+type Result struct {
+	Expectation Expectation
+
+	// Passed is whether the robots.txt meets the expectation.
+	Passed bool
+
+	// Message is why the expectation is not met, i.e. `googlebot can not crawl /cart/checkout, it is blocked by "Disallow: /cart"
+	// in the group of "*"`. It is empty when the expectation passed or there is an error.
+	Message string
+
+	// Err is why the expectation could not be checked, i.e. robotstxt.ErrOriginMismatch for a URL of another site.
+	Err error
+}
+
+// String describes a result for people, i.e. `googlebot /products/* allow: googlebot can not crawl ...`. It does not say where the
+// expectation is, "path:line: " in front of it does.
+func (result Result) String() string {
+	description := result.Expectation.String()
+	switch {
+	case result.Err != nil:
+		description += ": " + result.Err.Error()
+	case !result.Passed:
+		description += ": " + result.Message
+	default:
+		description += ": ok"
+	}
+	if result.Expectation.Comment != "" {
+		description += " (" + result.Expectation.Comment + ")"
+	}
+	return description
+}
+
+// Run checks the expectations against a robots.txt, there is a Result for every expectation in the same order.
+func Run(robotsTxt *robotstxt.RobotsTxt, expectations []Expectation) []Result {
+	results := make([]Result, len(expectations))
+	for i, expectation := range expectations {
+		results[i] = run(robotsTxt, expectation)
+	}
+	return results
+}
+
+func run(robotsTxt *robotstxt.RobotsTxt, expectation Expectation) Result {
+	result := Result{Expectation: expectation}
+	if !strings.HasSuffix(expectation.URL, "*") {
+		explanation, err := robotsTxt.Explain(expectation.Agent, expectation.URL)
+		if err != nil {
+			result.Err = err
+			return result
+		}
+		result.Passed = explanation.CanCrawl == expectation.Allow
+		if !result.Passed {
+			result.Message = explain(expectation.Agent, expectation.URL, explanation)
+		}
+		return result
+	}
+
+	prefix := strings.TrimSuffix(expectation.URL, "*")
+	subtree, err := robotsTxt.SubtreeStatus(expectation.Agent, prefix)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	counterexamples := subtree.Blocked
+	if !expectation.Allow {
+		counterexamples = subtree.Allowed
+	}
+	result.Passed = len(counterexamples) == 0
+	if !result.Passed {
+		// The counterexample is a path, the origin of an absolute URL is left out so it is not checked again.
+		explanation, err := robotsTxt.Explain(expectation.Agent, counterexamples[0])
+		if err != nil {
+			result.Err = err
+			return result
+		}
+		result.Message = explain(expectation.Agent, counterexamples[0], explanation)
+	}
+	return result
+}
+
+// explain describes why a robot can or can not crawl a URL, i.e. `googlebot can not crawl /cart, it is blocked by "Disallow: /cart"
+// in the group of "*"`.
+func explain(agent, url string, explanation robotstxt.Explanation) string {
+	message := agent + " can crawl " + url
+	if !explanation.CanCrawl {
+		message = agent + " can not crawl " + url
+	}
+	switch {
+	case explanation.Agent == "":
+		return message + ", no group applies to it"
+	case explanation.Rule == nil:
+		return message + ", no rule of the group of " + strconv.Quote(explanation.Agent) + " matches"
+	case explanation.CanCrawl:
+		message += ", it is allowed by "
+	default:
+		message += ", it is blocked by "
+	}
+	return message + strconv.Quote(explanation.Rule.String()) + " in the group of " + strconv.Quote(explanation.Agent)
+}
+
+// TestingT is the part of *testing.T that Check uses.
+type TestingT interface {
+	Errorf(format string, args ...interface{})
+}
+
+// Check runs the expectations of the expectations file at a path against a robots.txt and reports every expectation that is not
+// met as an error of the test.
+func Check(t TestingT, robotsTxt *robotstxt.RobotsTxt, path string) {
+	if helper, ok := t.(interface{ Helper() }); ok {
+		helper.Helper()
+	}
+	expectations, err := ParseFile(path)
+	if err != nil {
+		t.Errorf("%s: %v", path, err)
+		return
+	}
+	for _, result := range Run(robotsTxt, expectations) {
+		if result.Err != nil || !result.Passed {
+			t.Errorf("%s:%d: %s", path, result.Expectation.Line, result)
+		}
+	}
+}
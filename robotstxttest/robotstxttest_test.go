@@ -0,0 +1,157 @@
+package robotstxttest_test
+
+import (
+	"errors"
+	"fmt"
+	"github.com/itmayziii/robotstxt/v2"
+	"github.com/itmayziii/robotstxt/v2/robotstxttest"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const robotsTxtSource = `User-agent: *
+Disallow: /cart
+Disallow: /products/private/
+
+User-agent: googlebot
+Disallow: /cart
+`
+
+const expectationsSource = `# The product pages bring in all of our traffic.
+googlebot  /products/*  allow  # Googlebot must be able to crawl /products/*
+*          /cart        deny   # Everything must be blocked from /cart
+
+bingbot	https://www.dumpsters.com/products/*	ALLOW
+*          /checkout    deny
+`
+
+func newRobotsTxt(t *testing.T) *robotstxt.RobotsTxt {
+	robotsTxt, err := robotstxt.New("https://www.dumpsters.com", strings.NewReader(robotsTxtSource))
+	assert.Nil(t, err)
+	return robotsTxt
+}
+
+func TestParse(t *testing.T) {
+	expectations, err := robotstxttest.Parse(strings.NewReader(expectationsSource))
+	assert.Nil(t, err)
+	assert.Equal(t, []robotstxttest.Expectation{
+		{Line: 2, Agent: "googlebot", URL: "/products/*", Allow: true, Comment: "Googlebot must be able to crawl /products/*"},
+		{Line: 3, Agent: "*", URL: "/cart", Allow: false, Comment: "Everything must be blocked from /cart"},
+		{Line: 5, Agent: "bingbot", URL: "https://www.dumpsters.com/products/*", Allow: true},
+		{Line: 6, Agent: "*", URL: "/checkout", Allow: false},
+	}, expectations)
+	assert.Equal(t, "googlebot /products/* allow", expectations[0].String())
+	assert.Equal(t, "* /cart deny", expectations[1].String())
+}
+
+func TestParse_syntax_errors(t *testing.T) {
+	tests := []struct {
+		source string
+		err    string
+	}{
+		{"googlebot /cart\n", "line 1: expected an agent, a URL, and allow or deny but found 2 fields"},
+		{"\n# deny\ngooglebot /cart deny now\n", "line 3: expected an agent, a URL, and allow or deny but found 4 fields"},
+		{"googlebot /cart block\n", `line 1: expected allow or deny but found "block"`},
+	}
+	for _, test := range tests {
+		_, err := robotstxttest.Parse(strings.NewReader(test.source))
+		var syntaxError *robotstxttest.SyntaxError
+		assert.True(t, errors.As(err, &syntaxError), test.source)
+		assert.EqualError(t, err, test.err)
+	}
+}
+
+func TestRun(t *testing.T) {
+	expectations, err := robotstxttest.Parse(strings.NewReader(expectationsSource))
+	assert.Nil(t, err)
+	results := robotstxttest.Run(newRobotsTxt(t), expectations)
+	assert.Len(t, results, 4)
+
+	assert.True(t, results[0].Passed)
+	assert.Equal(t, "googlebot /products/* allow: ok (Googlebot must be able to crawl /products/*)", results[0].String())
+	assert.True(t, results[1].Passed)
+
+	// Bingbot follows the group of "*".
+	assert.False(t, results[2].Passed)
+	assert.Equal(t, `bingbot can not crawl /products/private/, it is blocked by "Disallow: /products/private/" in the group of "*"`,
+		results[2].Message)
+	assert.False(t, results[3].Passed)
+	assert.Equal(t, `* /checkout deny: * can crawl /checkout, no rule of the group of "*" matches`, results[3].String())
+
+	results = robotstxttest.Run(newRobotsTxt(t), []robotstxttest.Expectation{
+		{Line: 1, Agent: "googlebot", URL: "/cart/*"},
+		{Line: 2, Agent: "googlebot", URL: "/products/*"},
+		{Line: 3, Agent: "otherbot", URL: "https://www.example.com/", Allow: true},
+	})
+	assert.True(t, results[0].Passed)
+	assert.False(t, results[1].Passed)
+	assert.Equal(t, `googlebot can crawl /products/, no rule of the group of "googlebot" matches`, results[1].Message)
+	assert.Equal(t, robotstxt.ErrOriginMismatch, results[2].Err)
+	assert.Equal(t, "otherbot https://www.example.com/ allow: "+robotstxt.ErrOriginMismatch.Error(), results[2].String())
+}
+
+// An expectation for directives that are too complex to check every path of fails with an error instead of hanging.
+func TestRun_too_complex(t *testing.T) {
+	source := "User-agent: *\nDisallow: /\n"
+	for i := 0; i < 10; i++ {
+		source += fmt.Sprintf("Disallow: /*%c*%c*%c\n", 'a'+i, 'a'+(i+3)%26, 'a'+(i+7)%26)
+	}
+	robotsTxt, err := robotstxt.New("https://www.dumpsters.com", strings.NewReader(source))
+	assert.Nil(t, err)
+
+	results := robotstxttest.Run(robotsTxt, []robotstxttest.Expectation{{Line: 1, Agent: "googlebot", URL: "/*"}})
+	assert.False(t, results[0].Passed)
+	assert.Equal(t, robotstxt.ErrTooComplex, results[0].Err)
+}
+
+type recordingT struct {
+	errors []string
+}
+
+func (t *recordingT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func TestCheck(t *testing.T) {
+	dir, err := ioutil.TempDir("", "robotstxt")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "expectations.txt")
+	assert.Nil(t, ioutil.WriteFile(path, []byte(expectationsSource), 0644))
+
+	recorder := &recordingT{}
+	robotstxttest.Check(recorder, newRobotsTxt(t), path)
+	assert.Equal(t, []string{
+		path + `:5: bingbot https://www.dumpsters.com/products/* allow: bingbot can not crawl /products/private/, it is blocked by ` +
+			`"Disallow: /products/private/" in the group of "*"`,
+		path + `:6: * /checkout deny: * can crawl /checkout, no rule of the group of "*" matches`,
+	}, recorder.errors)
+
+	recorder = &recordingT{}
+	robotstxttest.Check(recorder, newRobotsTxt(t), filepath.Join(dir, "missing.txt"))
+	assert.Len(t, recorder.errors, 1)
+
+	// Check works with *testing.T.
+	assert.Nil(t, ioutil.WriteFile(path, []byte("googlebot /products/* allow\n"), 0644))
+	robotstxttest.Check(t, newRobotsTxt(t), path)
+}
+
+func ExampleRun() {
+	robotsTxt, _ := robotstxt.New("https://www.dumpsters.com", strings.NewReader("User-agent: *\nDisallow: /cart\n"))
+	expectations, _ := robotstxttest.Parse(strings.NewReader(`
+googlebot /products/* allow
+*         /cart/*     deny   # Everything must be blocked from /cart
+*         /checkout   deny
+`))
+	for _, result := range robotstxttest.Run(robotsTxt, expectations) {
+		fmt.Printf("line %d: %s\n", result.Expectation.Line, result)
+	}
+	// Output:
+	// line 2: googlebot /products/* allow: ok
+	// line 3: * /cart/* deny: ok (Everything must be blocked from /cart)
+	// line 4: * /checkout deny: * can crawl /checkout, no rule of the group of "*" matches
+}
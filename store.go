@@ -0,0 +1,78 @@
+package robotstxt
+
+import (
+	"sync"
+)
+
+/*
+Store persists cache entries by origin so a Cache can outlive the process it was created in. Entries handed to a Store keep the raw
+body and fetch metadata of the robots.txt, a Store does not need to keep the parsed RobotsTxt since the Cache parses the body again
+whenever an entry is returned without one. This also means stored entries pick up any changes to the parser.
+
+Implementations must be safe for concurrent use.
+*/
+type Store interface {
+	// Get returns the entry for a normalized origin, i.e. https://www.dumpsters.com:443, and false when there is none.
+	Get(origin string) (*CacheEntry, bool, error)
+
+	// Put adds or replaces the entry for the origin of the entry.
+	Put(cacheEntry *CacheEntry) error
+
+	// Delete removes the entry for a normalized origin, it is not an error if there is none.
+	Delete(origin string) error
+
+	// Iterate calls fn for every stored entry until fn returns false.
+	Iterate(fn func(cacheEntry *CacheEntry) bool) error
+}
+
+// MemoryStore is a Store that keeps everything in memory, it is what a Cache uses when no Store is configured. The zero value is
+// ready to use.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	entries map[string]*CacheEntry
+}
+
+// Get returns the entry for a normalized origin.
+func (memoryStore *MemoryStore) Get(origin string) (*CacheEntry, bool, error) {
+	memoryStore.mu.RLock()
+	defer memoryStore.mu.RUnlock()
+	cacheEntry, exists := memoryStore.entries[origin]
+	return cacheEntry, exists, nil
+}
+
+// Put adds or replaces the entry for the origin of the entry.
+func (memoryStore *MemoryStore) Put(cacheEntry *CacheEntry) error {
+	memoryStore.mu.Lock()
+	defer memoryStore.mu.Unlock()
+	if memoryStore.entries == nil {
+		memoryStore.entries = make(map[string]*CacheEntry)
+	}
+	memoryStore.entries[cacheEntry.Origin] = cacheEntry
+	return nil
+}
+
+// Delete removes the entry for a normalized origin.
+func (memoryStore *MemoryStore) Delete(origin string) error {
+	memoryStore.mu.Lock()
+	defer memoryStore.mu.Unlock()
+	delete(memoryStore.entries, origin)
+	return nil
+}
+
+// Iterate calls fn for every stored entry until fn returns false.
+func (memoryStore *MemoryStore) Iterate(fn func(cacheEntry *CacheEntry) bool) error {
+	// Take a snapshot so fn is free to modify the store.
+	memoryStore.mu.RLock()
+	cacheEntries := make([]*CacheEntry, 0, len(memoryStore.entries))
+	for _, cacheEntry := range memoryStore.entries {
+		cacheEntries = append(cacheEntries, cacheEntry)
+	}
+	memoryStore.mu.RUnlock()
+
+	for _, cacheEntry := range cacheEntries {
+		if !fn(cacheEntry) {
+			break
+		}
+	}
+	return nil
+}
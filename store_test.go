@@ -0,0 +1,207 @@
+package robotstxt_test
+
+import (
+	"context"
+	"errors"
+	"github.com/itmayziii/robotstxt/v2"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, &robotstxt.MemoryStore{})
+}
+
+func TestFileStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "robotstxt")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	fileStore, err := robotstxt.NewFileStore(dir)
+	assert.Nil(t, err)
+	testStore(t, fileStore)
+
+	// The metadata and body of an entry are one file so they are always replaced together.
+	assert.Nil(t, fileStore.Put(&robotstxt.CacheEntry{Origin: "https://www.dumpsters.com:443", Body: []byte("User-agent: *\n")}))
+	paths, err := filepath.Glob(filepath.Join(dir, "*"))
+	assert.Nil(t, err)
+	assert.Len(t, paths, 2) // The entry of http://www.dumpsters.com:80 is the other one.
+	path := filepath.Join(dir, "https%3A%2F%2Fwww.dumpsters.com%3A443.robotstxt")
+	data, err := ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"origin":"https://www.dumpsters.com:443","statusCode":0,"fetchedAt":"0001-01-01T00:00:00Z",`+
+		`"expires":"0001-01-01T00:00:00Z"}`+"\nUser-agent: *\n", string(data))
+
+	assert.Nil(t, ioutil.WriteFile(path, []byte("User-agent: *"), 0644))
+	_, _, err = fileStore.Get("https://www.dumpsters.com:443")
+	assert.NotNil(t, err)
+}
+
+func TestCache_with_FileStore_survives_a_restart(t *testing.T) {
+	dir, err := ioutil.TempDir("", "robotstxt")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		_, _ = w.Write([]byte("User-agent: *\nDisallow: /cms/\n"))
+	}))
+	defer server.Close()
+
+	fileStore, err := robotstxt.NewFileStore(dir)
+	assert.Nil(t, err)
+	_, err = (&robotstxt.Cache{Store: fileStore}).Get(context.Background(), server.URL)
+	assert.Nil(t, err)
+
+	// A new process would start with a new Cache and FileStore pointing at the same directory.
+	fileStore, err = robotstxt.NewFileStore(dir)
+	assert.Nil(t, err)
+	cache := &robotstxt.Cache{Store: fileStore}
+	robotsTxt, err := cache.Get(context.Background(), server.URL)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+	assert.Equal(t, robotstxt.CacheStats{Hits: 1}, cache.Stats())
+
+	canCrawl, err := robotsTxt.CanCrawl("googlebot", "/cms/pages")
+	assert.Nil(t, err)
+	assert.False(t, canCrawl)
+}
+
+func TestCache_refetches_entries_the_Store_can_not_read(t *testing.T) {
+	dir, err := ioutil.TempDir("", "robotstxt")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		_, _ = w.Write([]byte("User-agent: *\nDisallow: /cms/\n"))
+	}))
+	defer server.Close()
+
+	fileStore, err := robotstxt.NewFileStore(dir)
+	assert.Nil(t, err)
+	_, err = (&robotstxt.Cache{Store: fileStore}).Get(context.Background(), server.URL)
+	assert.Nil(t, err)
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.robotstxt"))
+	assert.Nil(t, err)
+	assert.Len(t, paths, 1)
+	assert.Nil(t, ioutil.WriteFile(paths[0], []byte("{"), 0644))
+
+	// The broken entry is fetched again and overwritten.
+	cache := &robotstxt.Cache{Store: fileStore}
+	robotsTxt, err := cache.Get(context.Background(), server.URL)
+	assert.Nil(t, err)
+	canCrawl, err := robotsTxt.CanCrawl("googlebot", "/cms/pages")
+	assert.Nil(t, err)
+	assert.False(t, canCrawl)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+	assert.Equal(t, robotstxt.CacheStats{Misses: 1, StoreErrors: 1}, cache.Stats())
+
+	cache = &robotstxt.Cache{Store: fileStore}
+	_, err = cache.Get(context.Background(), server.URL)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+	assert.Equal(t, robotstxt.CacheStats{Hits: 1}, cache.Stats())
+}
+
+type brokenStore struct {
+	robotstxt.MemoryStore
+	gets int32
+}
+
+func (brokenStore *brokenStore) Get(origin string) (*robotstxt.CacheEntry, bool, error) {
+	atomic.AddInt32(&brokenStore.gets, 1)
+	return brokenStore.MemoryStore.Get(origin)
+}
+
+func (brokenStore *brokenStore) Put(cacheEntry *robotstxt.CacheEntry) error {
+	return errors.New("disk full")
+}
+
+func TestCache_keeps_entries_the_Store_can_not_write(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		_, _ = w.Write([]byte("User-agent: *\nDisallow: /cms/\n"))
+	}))
+	defer server.Close()
+
+	store := &brokenStore{}
+	cache := &robotstxt.Cache{Store: store}
+	for i := 0; i < 3; i++ {
+		robotsTxt, err := cache.Get(context.Background(), server.URL)
+		assert.Nil(t, err)
+		canCrawl, err := robotsTxt.CanCrawl("googlebot", "/cms/pages")
+		assert.Nil(t, err)
+		assert.False(t, canCrawl)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+	assert.Equal(t, robotstxt.CacheStats{Hits: 2, Misses: 1, StoreErrors: 1}, cache.Stats())
+
+	// Entries are kept in memory, the Store is only read for the first lookup.
+	assert.Equal(t, int32(1), atomic.LoadInt32(&store.gets))
+}
+
+func testStore(t *testing.T, store robotstxt.Store) {
+	_, exists, err := store.Get("https://www.dumpsters.com:443")
+	assert.Nil(t, err)
+	assert.False(t, exists)
+
+	fetchedAt := time.Date(2019, 6, 15, 0, 0, 0, 0, time.UTC)
+	cacheEntry := &robotstxt.CacheEntry{
+		Origin:   "https://www.dumpsters.com:443",
+		URL:      "https://www.dumpsters.com/robots.txt",
+		FinalURL: "https://cdn.dumpsters.com/robots.txt",
+		Redirects: []robotstxt.Redirect{
+			{From: "https://www.dumpsters.com/robots.txt", To: "https://cdn.dumpsters.com/robots.txt", StatusCode: http.StatusFound},
+		},
+		StatusCode:   http.StatusOK,
+		Body:         []byte("User-agent: *\nDisallow: /cms/\n"),
+		FetchedAt:    fetchedAt,
+		Expires:      fetchedAt.Add(24 * time.Hour),
+		ETag:         `"v1"`,
+		LastModified: "Sat, 15 Jun 2019 00:00:00 GMT",
+	}
+	assert.Nil(t, store.Put(cacheEntry))
+	assert.Nil(t, store.Put(&robotstxt.CacheEntry{Origin: "http://www.dumpsters.com:80", StatusCode: http.StatusNotFound}))
+
+	stored, exists, err := store.Get("https://www.dumpsters.com:443")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, cacheEntry.Origin, stored.Origin)
+	assert.Equal(t, cacheEntry.URL, stored.URL)
+	assert.Equal(t, cacheEntry.FinalURL, stored.FinalURL)
+	assert.Equal(t, cacheEntry.Redirects, stored.Redirects)
+	assert.Equal(t, cacheEntry.StatusCode, stored.StatusCode)
+	assert.Equal(t, cacheEntry.Body, stored.Body)
+	assert.True(t, cacheEntry.FetchedAt.Equal(stored.FetchedAt))
+	assert.True(t, cacheEntry.Expires.Equal(stored.Expires))
+	assert.Equal(t, cacheEntry.ETag, stored.ETag)
+	assert.Equal(t, cacheEntry.LastModified, stored.LastModified)
+
+	var origins []string
+	err = store.Iterate(func(cacheEntry *robotstxt.CacheEntry) bool {
+		origins = append(origins, cacheEntry.Origin)
+		return true
+	})
+	assert.Nil(t, err)
+	sort.Strings(origins)
+	assert.Equal(t, []string{"http://www.dumpsters.com:80", "https://www.dumpsters.com:443"}, origins)
+
+	assert.Nil(t, store.Delete("https://www.dumpsters.com:443"))
+	assert.Nil(t, store.Delete("https://www.dumpsters.com:443"))
+	_, exists, err = store.Get("https://www.dumpsters.com:443")
+	assert.Nil(t, err)
+	assert.False(t, exists)
+}
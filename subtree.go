@@ -0,0 +1,109 @@
+package robotstxt
+
+import (
+	netUrl "net/url"
+	"strings"
+)
+
+// maxSubtreeExamples is how many example paths SubtreeStatus returns for each side.
+const maxSubtreeExamples = 3
+
+// SubtreeStatus is whether a robot can crawl all, none, or some of the paths that start with a prefix, see RobotsTxt.SubtreeStatus.
+type SubtreeStatus int
+
+const (
+	// AllAllowed is a prefix the robot can crawl every path of.
+	AllAllowed SubtreeStatus = iota
+
+	// AllBlocked is a prefix the robot can not crawl any path of.
+	AllBlocked
+
+	// Mixed is a prefix with paths the robot can crawl and paths it can not crawl.
+	Mixed
+)
+
+func (status SubtreeStatus) String() string {
+	switch status {
+	case AllAllowed:
+		return "all allowed"
+	case AllBlocked:
+		return "all blocked"
+	}
+	return "mixed"
+}
+
+// Subtree is what SubtreeStatus finds for a prefix, whether a robot can crawl its paths together with examples of them.
+type Subtree struct {
+	Status SubtreeStatus
+
+	// Allowed are examples of paths of the subtree the robot can crawl, the shortest path for each directive that decides paths of the
+	// subtree and at most 3 of them. It is empty when the status is AllBlocked.
+	Allowed []string
+
+	// Blocked are examples of paths of the subtree the robot can not crawl the same way. It is empty when the status is AllAllowed.
+	Blocked []string
+}
+
+/*
+SubtreeStatus reports whether a robot (user-agent) can crawl every path that starts with a prefix, none of them, or only some of
+them. Every path is considered, not a sample of them, so a single disallowed path anywhere below the prefix makes the status Mixed:
+
+	User-agent: *
+	Disallow: /cms/
+	Allow: /cms/public/
+
+	subtree, err := robotsTxt.SubtreeStatus("googlebot", "/cms/")
+	// subtree.Status = Mixed
+	// subtree.Allowed = []string{"/cms/public/"}
+	// subtree.Blocked = []string{"/cms/"}
+
+The prefix is a path or an absolute URL the same way as for CanCrawl, its query and fragment are part of it and ignored
+respectively. ErrTooComplex is returned when the directives have more ways to match a path than there is time to look at and no
+path of either kind was found before.
+*/
+func (robotsTxt *RobotsTxt) SubtreeStatus(robotName, prefix string) (Subtree, error) {
+	parsedUrl, err := netUrl.Parse(prefix)
+	if err != nil {
+		return Subtree{}, err
+	}
+	err = robotsTxt.checkOrigin(parsedUrl)
+	if err != nil {
+		return Subtree{}, err
+	}
+	normalizedPath := parsedUrl.RequestURI()
+	if !strings.HasPrefix(normalizedPath, "/") {
+		normalizedPath = "/" + normalizedPath
+	}
+
+	subtree := Subtree{}
+	rules := robotsTxt.agentRules(robotName)
+	automaton := newAutomaton(rules)
+	decided := make(map[int]bool) // The directives that decide a path of an example, -1 for no directive.
+	err = automaton.explore(automaton.walk(normalizedPath), normalizedPath, func(state *automatonState, path string) bool {
+		allowed, rule := decide(rules, automaton.matches(state))
+		if decided[rule] {
+			return true
+		}
+		decided[rule] = true
+		if allowed && len(subtree.Allowed) < maxSubtreeExamples {
+			subtree.Allowed = append(subtree.Allowed, path)
+		} else if !allowed && len(subtree.Blocked) < maxSubtreeExamples {
+			subtree.Blocked = append(subtree.Blocked, path)
+		}
+		return len(subtree.Allowed) < maxSubtreeExamples || len(subtree.Blocked) < maxSubtreeExamples
+	})
+	// A path of each kind is an answer even when not every path could be looked at.
+	if err != nil && (len(subtree.Allowed) == 0 || len(subtree.Blocked) == 0) {
+		return Subtree{}, err
+	}
+
+	switch {
+	case len(subtree.Blocked) == 0:
+		subtree.Status = AllAllowed
+	case len(subtree.Allowed) == 0:
+		subtree.Status = AllBlocked
+	default:
+		subtree.Status = Mixed
+	}
+	return subtree, nil
+}
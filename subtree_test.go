@@ -0,0 +1,174 @@
+package robotstxt_test
+
+import (
+	"fmt"
+	"github.com/itmayziii/robotstxt/v2"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRobotsTxt_SubtreeStatus(t *testing.T) {
+	robotsTxt := newRobotsTxt(t, `User-agent: *
+Disallow: /cms/
+Allow: /cms/public/
+Disallow: /*.pdf$
+
+User-agent: googlebot
+Disallow: /
+
+User-agent: bingbot
+Disallow:
+`)
+
+	subtree, err := robotsTxt.SubtreeStatus("otherbot", "/cms/")
+	assert.Nil(t, err)
+	assert.Equal(t, robotstxt.Mixed, subtree.Status)
+	assert.Equal(t, []string{"/cms/public/"}, subtree.Allowed)
+	assert.Equal(t, []string{"/cms/", "/cms/.pdf"}, subtree.Blocked)
+
+	subtree, err = robotsTxt.SubtreeStatus("otherbot", "/cms/private/")
+	assert.Nil(t, err)
+	assert.Equal(t, robotstxt.AllBlocked, subtree.Status)
+	assert.Empty(t, subtree.Allowed)
+	assert.Equal(t, []string{"/cms/private/", "/cms/private/.pdf"}, subtree.Blocked)
+
+	// A pdf can be anywhere below a prefix.
+	subtree, err = robotsTxt.SubtreeStatus("otherbot", "https://www.dumpsters.com/products/")
+	assert.Nil(t, err)
+	assert.Equal(t, robotstxt.Mixed, subtree.Status)
+	assert.Equal(t, []string{"/products/.pdf"}, subtree.Blocked[:1])
+
+	subtree, err = robotsTxt.SubtreeStatus("otherbot", "/cms/?page=")
+	assert.Nil(t, err)
+	assert.Equal(t, robotstxt.AllBlocked, subtree.Status)
+	assert.Equal(t, "/cms/?page=", subtree.Blocked[0])
+
+	subtree, err = robotsTxt.SubtreeStatus("Googlebot", "")
+	assert.Nil(t, err)
+	assert.Equal(t, robotstxt.AllBlocked, subtree.Status)
+
+	subtree, err = robotsTxt.SubtreeStatus("bingbot", "/")
+	assert.Nil(t, err)
+	assert.Equal(t, robotstxt.AllAllowed, subtree.Status)
+	assert.Equal(t, []string{"/"}, subtree.Allowed)
+	assert.Empty(t, subtree.Blocked)
+
+	_, err = robotsTxt.SubtreeStatus("otherbot", "https://www.example.com/cms/")
+	assert.Equal(t, robotstxt.ErrOriginMismatch, err)
+}
+
+// A "." or a "?" in a pattern only matches itself, so every witness has them where the pattern has them.
+func TestRobotsTxt_SubtreeStatus_witnesses_of_special_characters(t *testing.T) {
+	robotsTxt := newRobotsTxt(t, `User-agent: *
+Disallow: /search?q=
+Allow: /search?q=*.html$
+Disallow: /api/*.json
+`)
+
+	subtree, err := robotsTxt.SubtreeStatus("otherbot", "/search")
+	assert.Nil(t, err)
+	assert.Equal(t, robotstxt.Mixed, subtree.Status)
+	assert.Equal(t, []string{"/search", "/search?q=.html"}, subtree.Allowed)
+	assert.Equal(t, []string{"/search?q="}, subtree.Blocked)
+
+	subtree, err = robotsTxt.SubtreeStatus("otherbot", "/api/")
+	assert.Nil(t, err)
+	assert.Equal(t, robotstxt.Mixed, subtree.Status)
+	assert.Equal(t, []string{"/api/"}, subtree.Allowed)
+	assert.Equal(t, []string{"/api/.json"}, subtree.Blocked)
+
+	for _, path := range append(subtree.Allowed, subtree.Blocked...) {
+		canCrawl, _ := robotsTxt.CanCrawl("otherbot", path)
+		assert.Equal(t, canCrawl, path == "/api/", path)
+	}
+	canCrawl, _ := robotsTxt.CanCrawl("otherbot", "/api/xjson")
+	assert.True(t, canCrawl)
+	canCrawl, _ = robotsTxt.CanCrawl("otherbot", "/searchxq=")
+	assert.True(t, canCrawl)
+}
+
+// Directives with many stars have more ways to match a path than there is time to look at, only a Mixed subtree can be told then.
+func TestRobotsTxt_SubtreeStatus_too_complex(t *testing.T) {
+	robotsTxt := newRobotsTxt(t, hostileRobotsTxt(10)+"Disallow: /\n")
+	start := time.Now()
+	_, err := robotsTxt.SubtreeStatus("otherbot", "/")
+	assert.Equal(t, robotstxt.ErrTooComplex, err)
+	assert.True(t, time.Since(start) < 5*time.Second, time.Since(start).String())
+
+	robotsTxt = newRobotsTxt(t, hostileRobotsTxt(10)+"Allow: /cms/\n")
+	subtree, err := robotsTxt.SubtreeStatus("otherbot", "/")
+	assert.Nil(t, err)
+	assert.Equal(t, robotstxt.Mixed, subtree.Status)
+}
+
+func TestSubtreeStatus_String(t *testing.T) {
+	assert.Equal(t, "all allowed", robotstxt.AllAllowed.String())
+	assert.Equal(t, "all blocked", robotstxt.AllBlocked.String())
+	assert.Equal(t, "mixed", robotstxt.Mixed.String())
+}
+
+func TestRobotsTxt_SubtreeStatus_agrees_with_CanCrawl(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for round := 0; round < 100; round++ {
+		source := "User-agent: *\n"
+		for i := random.Intn(6); i >= 0; i-- {
+			directive := "Disallow: /"
+			if random.Intn(2) == 0 {
+				directive = "Allow: /"
+			}
+			for j := random.Intn(4); j >= 0; j-- {
+				directive += string("ab/*"[random.Intn(4)])
+			}
+			if random.Intn(4) == 0 {
+				directive += "$"
+			}
+			source += directive + "\n"
+		}
+		robotsTxt := newRobotsTxt(t, source)
+
+		prefix := "/"
+		for j := random.Intn(3); j > 0; j-- {
+			prefix += string("ab"[random.Intn(2)])
+		}
+		subtree, err := robotsTxt.SubtreeStatus("otherbot", prefix)
+		assert.Nil(t, err)
+		for _, path := range subtree.Allowed {
+			canCrawl, _ := robotsTxt.CanCrawl("otherbot", path)
+			assert.True(t, canCrawl, "%s %s", source, path)
+		}
+		for _, path := range subtree.Blocked {
+			canCrawl, _ := robotsTxt.CanCrawl("otherbot", path)
+			assert.False(t, canCrawl, "%s %s", source, path)
+		}
+
+		// Every path below the prefix that is sampled has to agree with the status.
+		for i := 0; i < 30; i++ {
+			path := prefix
+			for j := random.Intn(5); j > 0; j-- {
+				path += string("ab/"[random.Intn(3)])
+			}
+			if strings.HasPrefix(path, "//") {
+				continue // The host of a URL.
+			}
+			canCrawl, _ := robotsTxt.CanCrawl("otherbot", path)
+			if canCrawl {
+				assert.NotEqual(t, robotstxt.AllBlocked, subtree.Status, "%s %s", source, path)
+			} else {
+				assert.NotEqual(t, robotstxt.AllAllowed, subtree.Status, "%s %s", source, path)
+			}
+		}
+	}
+}
+
+func ExampleRobotsTxt_SubtreeStatus() {
+	robotsTxt, _ := robotstxt.New("https://www.dumpsters.com", strings.NewReader(`User-agent: *
+Disallow: /cms/
+Allow: /cms/public/
+`))
+	subtree, _ := robotsTxt.SubtreeStatus("googlebot", "/cms/")
+	fmt.Println(subtree.Status, subtree.Allowed[0], subtree.Blocked[0])
+	// Output: mixed /cms/public/ /cms/
+}
@@ -0,0 +1,114 @@
+package robotstxt
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+)
+
+// ErrDisallowed is the error every DisallowedError matches with errors.Is.
+var ErrDisallowed = errors.New("disallowed by robots.txt")
+
+// DisallowedError is returned by RoundTripper when the robots.txt of a site does not allow a request.
+type DisallowedError struct {
+	// URL is the URL of the request.
+	URL string
+
+	// RobotName is the robot (user-agent) the robots.txt was checked for.
+	RobotName string
+
+	// Rule is the path of the disallow directive that matched the URL, i.e. "/cms/".
+	Rule string
+}
+
+func (disallowedError *DisallowedError) Error() string {
+	return disallowedError.URL + " is disallowed for " + disallowedError.RobotName + " by robots.txt rule \"Disallow: " +
+		disallowedError.Rule + "\""
+}
+
+// Is makes errors.Is(err, ErrDisallowed) true for every DisallowedError.
+func (disallowedError *DisallowedError) Is(target error) bool {
+	return target == ErrDisallowed
+}
+
+/*
+RoundTripper is an http.RoundTripper that refuses to make requests that the robots.txt of the target site does not allow, so
+robots.txt is enforced for every request made through an http.Client that uses it:
+
+	client := &http.Client{Transport: &robotstxt.RoundTripper{RobotName: "dumpsterbot"}}
+	_, err := client.Get("https://www.dumpsters.com/cms/pages")
+	errors.Is(err, robotstxt.ErrDisallowed) // True
+
+Disallowed requests fail with a *DisallowedError without ever reaching the wrapped transport. Requests for /robots.txt itself are
+always allowed which also means Robots can fetch through an http.Client that uses this RoundTripper.
+
+The zero value is ready to use and a RoundTripper is safe for concurrent use.
+*/
+type RoundTripper struct {
+	// Transport makes the requests that are allowed, http.DefaultTransport is used when nil.
+	Transport http.RoundTripper
+
+	// Robots retrieves the robots.txt for each request. When nil a Registry is used that fetches through Transport.
+	Robots Getter
+
+	// RobotName is the robot (user-agent) that robots.txt is checked for, the "User-Agent" header of each request is used when empty.
+	RobotName string
+
+	once   sync.Once
+	robots Getter
+}
+
+// RoundTrip executes a single HTTP transaction if the robots.txt of the target site allows it.
+func (roundTripper *RoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Path == "/robots.txt" {
+		return roundTripper.transport().RoundTrip(req)
+	}
+
+	robotsTxt, err := roundTripper.getter().Get(req.Context(), req.URL.String())
+	if err != nil {
+		closeRequestBody(req)
+		return nil, err
+	}
+
+	robotName := roundTripper.RobotName
+	if robotName == "" {
+		robotName = req.UserAgent()
+	}
+	robot, exists := findMatchingRobot(robotName, robotsTxt.robots)
+	canCrawl, rule, err := robotsTxt.canCrawl(robot, exists, req.URL)
+	if err != nil {
+		closeRequestBody(req)
+		return nil, err
+	}
+	if !canCrawl {
+		closeRequestBody(req)
+		return nil, &DisallowedError{URL: req.URL.String(), RobotName: robotName, Rule: rule}
+	}
+
+	return roundTripper.transport().RoundTrip(req)
+}
+
+func (roundTripper *RoundTripper) transport() http.RoundTripper {
+	if roundTripper.Transport != nil {
+		return roundTripper.Transport
+	}
+	return http.DefaultTransport
+}
+
+func (roundTripper *RoundTripper) getter() Getter {
+	roundTripper.once.Do(func() {
+		roundTripper.robots = roundTripper.Robots
+		if roundTripper.robots == nil {
+			fetcher := &Fetcher{Client: &http.Client{Transport: roundTripper.transport()}, UserAgent: roundTripper.RobotName}
+			roundTripper.robots = &Registry{Getter: fetcher}
+		}
+	})
+	return roundTripper.robots
+}
+
+// closeRequestBody closes the body of a request that is not going to be sent, a RoundTripper must always close the body.
+func closeRequestBody(req *http.Request) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+}
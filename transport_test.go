@@ -0,0 +1,84 @@
+package robotstxt_test
+
+import (
+	"errors"
+	"github.com/itmayziii/robotstxt/v2"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRoundTripper(t *testing.T) {
+	var robotsTxtRequests, pageRequests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			atomic.AddInt32(&robotsTxtRequests, 1)
+			_, _ = w.Write([]byte("User-agent: *\nDisallow: /cms/\n\nUser-agent: dumpsterbot\nDisallow: /pricing/\n"))
+			return
+		}
+		atomic.AddInt32(&pageRequests, 1)
+	}))
+	defer server.Close()
+
+	roundTripper := &robotstxt.RoundTripper{}
+	client := &http.Client{Transport: roundTripper}
+
+	resp, err := client.Get(server.URL + "/products/")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	_ = resp.Body.Close()
+
+	_, err = client.Get(server.URL + "/cms/pages")
+	assert.True(t, errors.Is(err, robotstxt.ErrDisallowed))
+	var disallowedError *robotstxt.DisallowedError
+	assert.True(t, errors.As(err, &disallowedError))
+	assert.Equal(t, "/cms/", disallowedError.Rule)
+	assert.Equal(t, server.URL+"/cms/pages", disallowedError.URL)
+
+	// The User-Agent of the request decides which group applies.
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/pricing/", nil)
+	assert.Nil(t, err)
+	req.Header.Set("User-Agent", "dumpsterbot")
+	_, err = client.Do(req)
+	assert.True(t, errors.Is(err, robotstxt.ErrDisallowed))
+
+	// The robots.txt itself is never blocked.
+	resp, err = client.Get(server.URL + "/robots.txt")
+	assert.Nil(t, err)
+	_ = resp.Body.Close()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&pageRequests))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&robotsTxtRequests))
+}
+
+func TestRoundTripper_RobotName(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			assert.Equal(t, "dumpsterbot", r.Header.Get("User-Agent"))
+			_, _ = w.Write([]byte("User-agent: dumpsterbot\nDisallow: /pricing/\n"))
+		}
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &robotstxt.RoundTripper{RobotName: "dumpsterbot"}}
+	_, err := client.Get(server.URL + "/pricing/roll-off-dumpsters")
+	assert.True(t, errors.Is(err, robotstxt.ErrDisallowed))
+}
+
+func TestRoundTripper_fetching_robots_txt_through_itself(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			_, _ = w.Write([]byte("User-agent: *\nDisallow: /cms/\n"))
+		}
+	}))
+	defer server.Close()
+
+	roundTripper := &robotstxt.RoundTripper{}
+	client := &http.Client{Transport: roundTripper}
+	roundTripper.Robots = &robotstxt.Registry{Getter: &robotstxt.Fetcher{Client: client}}
+
+	_, err := client.Get(server.URL + "/cms/")
+	assert.True(t, errors.Is(err, robotstxt.ErrDisallowed))
+}
@@ -1,45 +1,22 @@
 package robotstxt
 
 import (
-	"errors"
+	"fmt"
+	"net"
 	netUrl "net/url"
-	"regexp"
 	"strings"
 )
 
-func urlMatchLength(url string, paths []string) (int, error) {
-	if paths == nil || len(paths) == 0 {
-		return 0, nil
-	}
-
-	matchLength := 0
-	for _, path := range paths {
-		// Handle the wildcards.
-		if strings.Contains(path, "*") || strings.Contains(path, "$") {
-			expression := strings.Replace(path, "*", "(.*)", -1)
-			regExp, err := regexp.Compile(expression)
-			if err != nil {
-				return 0, errors.New("unable to get length of path " + path)
-			}
-			match := regExp.FindString(url)
-			if match == "" {
-				continue
-			}
-
-			matchLength = len(path)
-			break
-		}
-
-		if strings.HasPrefix(url, path) {
-			matchLength = len(path)
-			break
-		}
+func findMatchingRobot(robotName string, robots map[string]robot) (robot, bool) {
+	matchedRobotName := matchingRobotName(robotName, robots)
+	if matchedRobotName == "" {
+		return robot{}, false
 	}
-
-	return matchLength, nil
+	return robots[matchedRobotName], true
 }
 
-func findMatchingRobot(robotName string, robots map[string]robot) (robot, bool) {
+// matchingRobotName returns the user agent of the group that applies to a robot, or an empty string when no group applies.
+func matchingRobotName(robotName string, robots map[string]robot) string {
 	// User agents are case insensitive.
 	// https://developers.google.com/search/reference/robots_txt#order-of-precedence-for-user-agents
 	robotName = strings.ToLower(robotName)
@@ -53,16 +30,14 @@ func findMatchingRobot(robotName string, robots map[string]robot) (robot, bool)
 	}
 
 	if matchedRobotName != "" {
-		return robots[matchedRobotName], true
+		return matchedRobotName
 	}
 
 	// If we made it this far then there is no matching robot, let's check for the wildcard.
-	allUserAgents, exists := robots["*"]
-	if !exists {
-		return robot{}, false
+	if _, exists := robots["*"]; exists {
+		return "*"
 	}
-
-	return allUserAgents, true
+	return ""
 }
 
 func keys(robots map[string]robot) []string {
@@ -83,14 +58,23 @@ func normalizeUrl(url string) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	return urlOrigin(parsedUrl)
+}
 
-	if parsedUrl.Scheme == "" || parsedUrl.Host == "" {
-		return "", errors.New("invalid URL provided for robot, the URL must have a valid schema and host, url = " + url)
+// urlOrigin is normalizeUrl for a URL that is already parsed. Scheme and host are case insensitive so they are lower cased, and the
+// user information is left out.
+func urlOrigin(url *netUrl.URL) (string, error) {
+	if url.Scheme == "" || url.Host == "" {
+		withoutUser := *url
+		withoutUser.User = nil
+		return "", fmt.Errorf("%w, url = %s", ErrInvalidURL, withoutUser.String())
 	}
 
-	urlPort := parsedUrl.Port()
+	scheme := strings.ToLower(url.Scheme)
+	host := strings.ToLower(url.Hostname())
+	urlPort := url.Port()
 	if urlPort == "" {
-		switch parsedUrl.Scheme {
+		switch scheme {
 		case "http":
 			urlPort = "80"
 			break
@@ -100,8 +84,8 @@ func normalizeUrl(url string) (string, error) {
 	}
 	// Give up on trying to use the port since we can't determine it from the Scheme.
 	if urlPort == "" {
-		return parsedUrl.Scheme + "://" + parsedUrl.Host, nil
+		return scheme + "://" + strings.ToLower(url.Host), nil
 	}
 
-	return parsedUrl.Scheme + "://" + parsedUrl.Hostname() + ":" + urlPort, nil
+	return scheme + "://" + net.JoinHostPort(host, urlPort), nil
 }
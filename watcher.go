@@ -0,0 +1,186 @@
+package robotstxt
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"io/ioutil"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// defaultWatchInterval is how often a Watcher looks at its file when no Interval is set.
+const defaultWatchInterval = time.Second
+
+// Reload is sent to the channels registered with Watcher.Notify after the file of a Watcher changed.
+type Reload struct {
+	// RobotsTxt is the RobotsTxt in use after the reload, it is the previous one when Err is set.
+	RobotsTxt *RobotsTxt
+
+	// Err is why the changed file could not be loaded.
+	Err error
+}
+
+/*
+Watcher keeps a RobotsTxt in sync with a file on disk that is edited while it is in use:
+
+	watcher := &robotstxt.Watcher{URL: "https://www.dumpsters.com", Path: "/srv/www/robots.txt"}
+	err := watcher.Load()
+	if err != nil {
+		return err
+	}
+	go watcher.Watch(ctx)
+
+	canCrawl, err := watcher.RobotsTxt().CanCrawl("googlebot", "/cms/pages")
+
+The file is read again every Interval and parsed when its content changed, the new RobotsTxt then replaces the old one in a single
+atomic step so callers of RobotsTxt always see a complete file. When a changed file can not be read or parsed the previous RobotsTxt
+stays in use. Channels registered with Notify hear about every change, including the ones that failed. An error is only reported again
+when it changes or the file does, a file that stays missing is reported once.
+
+A Watcher is also a Getter for its own origin, so it can be used as the Robots of a RoundTripper or Limiter. A Watcher is safe for
+concurrent use.
+*/
+type Watcher struct {
+	// URL is the scheme, host, and optional port the file applies to, i.e. https://www.dumpsters.com.
+	URL string
+
+	// Path is the location of the robots.txt file.
+	Path string
+
+	// Interval is how often the file is checked for changes, 1 second is used when zero.
+	Interval time.Duration
+
+	robotsTxt atomic.Value
+
+	mu       sync.Mutex
+	checksum [sha256.Size]byte
+	read     bool  // Set once checksum is the one of the file as it was last read.
+	parseErr error // Why the file with the checksum could not be parsed.
+	failed   error // The error that was last reported, nil after the file loaded.
+	notify   []chan<- Reload
+}
+
+// Load reads and parses the file if it changed since it was last loaded. An error means the previous RobotsTxt, if any, is still in
+// use.
+func (watcher *Watcher) Load() error {
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+
+	content, err := ioutil.ReadFile(watcher.Path)
+	if err != nil {
+		watcher.fail(err, false)
+		return err
+	}
+	checksum := sha256.Sum256(content)
+	if watcher.read && checksum == watcher.checksum {
+		// The file is not parsed again until it changes, a file that could not be parsed still can not be.
+		if watcher.parseErr != nil {
+			watcher.fail(watcher.parseErr, false)
+			return watcher.parseErr
+		}
+		watcher.failed = nil
+		return nil
+	}
+
+	watcher.checksum = checksum
+	watcher.read = true
+	robotsTxt, err := parse(watcher.URL, bytes.NewReader(content))
+	watcher.parseErr = err
+	if err != nil {
+		watcher.fail(err, true)
+		return err
+	}
+
+	watcher.robotsTxt.Store(robotsTxt)
+	watcher.failed = nil
+	watcher.send(Reload{RobotsTxt: robotsTxt})
+	return nil
+}
+
+// fail reports an error through Notify unless it is the error that was reported last and the file did not change since, there is
+// no point in reporting the same mistake every Interval.
+func (watcher *Watcher) fail(err error, changed bool) {
+	if changed || watcher.failed == nil || watcher.failed.Error() != err.Error() {
+		watcher.send(Reload{RobotsTxt: watcher.RobotsTxt(), Err: err})
+	}
+	watcher.failed = err
+}
+
+// Watch loads the file every Interval until ctx is done, it always returns the error of ctx. Errors of the individual loads are
+// only reported through Notify.
+func (watcher *Watcher) Watch(ctx context.Context) error {
+	interval := watcher.Interval
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_ = watcher.Load()
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// RobotsTxt returns the RobotsTxt that is currently in use, it is nil until the file loaded successfully once.
+func (watcher *Watcher) RobotsTxt() *RobotsTxt {
+	robotsTxt, _ := watcher.robotsTxt.Load().(*RobotsTxt)
+	return robotsTxt
+}
+
+// Get returns the RobotsTxt that is currently in use, the file is loaded first when that has not happened yet. URLs of other origins
+// get ErrOriginMismatch.
+func (watcher *Watcher) Get(ctx context.Context, url string) (*RobotsTxt, error) {
+	robotsTxt := watcher.RobotsTxt()
+	if robotsTxt == nil {
+		err := watcher.Load()
+		if err != nil {
+			return &RobotsTxt{}, err
+		}
+		robotsTxt = watcher.RobotsTxt()
+	}
+
+	origin, err := normalizeUrl(url)
+	if err != nil {
+		return &RobotsTxt{}, err
+	}
+	if origin != robotsTxt.URL() {
+		return &RobotsTxt{}, ErrOriginMismatch
+	}
+	return robotsTxt, nil
+}
+
+// Notify relays every Reload to ch. Sending to ch does not block, the caller has to make sure ch has enough buffer space to keep up
+// with the changes it cares about.
+func (watcher *Watcher) Notify(ch chan<- Reload) {
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+	watcher.notify = append(watcher.notify, ch)
+}
+
+// Stop stops relaying to ch.
+func (watcher *Watcher) Stop(ch chan<- Reload) {
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+	for i, notify := range watcher.notify {
+		if notify == ch {
+			watcher.notify = append(watcher.notify[:i], watcher.notify[i+1:]...)
+			return
+		}
+	}
+}
+
+func (watcher *Watcher) send(reload Reload) {
+	for _, ch := range watcher.notify {
+		select {
+		case ch <- reload:
+		default:
+		}
+	}
+}
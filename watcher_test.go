@@ -0,0 +1,150 @@
+package robotstxt_test
+
+import (
+	"context"
+	"errors"
+	"github.com/itmayziii/robotstxt/v2"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatcher_Load(t *testing.T) {
+	dir, path := writeRobotsTxtFile(t, "User-agent: *\nDisallow: /cms/\n")
+	defer os.RemoveAll(dir)
+	watcher := &robotstxt.Watcher{URL: "https://www.dumpsters.com", Path: path}
+	assert.Nil(t, watcher.RobotsTxt())
+
+	reloads := make(chan robotstxt.Reload, 10)
+	watcher.Notify(reloads)
+
+	assert.Nil(t, watcher.Load())
+	first := watcher.RobotsTxt()
+	canCrawl, err := first.CanCrawl("googlebot", "/cms/pages")
+	assert.Nil(t, err)
+	assert.False(t, canCrawl)
+	assert.Equal(t, first, (<-reloads).RobotsTxt)
+
+	// Nothing changed so nothing happens.
+	assert.Nil(t, watcher.Load())
+	assert.Equal(t, first, watcher.RobotsTxt())
+	assert.Len(t, reloads, 0)
+
+	assert.Nil(t, ioutil.WriteFile(path, []byte("User-agent: *\nDisallow: /pricing/\n"), 0644))
+	assert.Nil(t, watcher.Load())
+	second := watcher.RobotsTxt()
+	assert.NotEqual(t, first, second)
+	canCrawl, err = second.CanCrawl("googlebot", "/cms/pages")
+	assert.Nil(t, err)
+	assert.True(t, canCrawl)
+	assert.Equal(t, second, (<-reloads).RobotsTxt)
+
+	// A broken file keeps the previous version around.
+	assert.Nil(t, ioutil.WriteFile(path, []byte("User-agent: *\nCrawl-delay: soon\n"), 0644))
+	assert.NotNil(t, watcher.Load())
+	assert.Equal(t, second, watcher.RobotsTxt())
+	reload := <-reloads
+	assert.NotNil(t, reload.Err)
+	assert.Equal(t, second, reload.RobotsTxt)
+
+	assert.Nil(t, os.Remove(path))
+	assert.NotNil(t, watcher.Load())
+	assert.Equal(t, second, watcher.RobotsTxt())
+
+	watcher.Stop(reloads)
+	assert.Nil(t, ioutil.WriteFile(path, []byte("User-agent: *\nDisallow: /\n"), 0644))
+	assert.Nil(t, watcher.Load())
+	<-reloads // The error of the removed file.
+	assert.Len(t, reloads, 0)
+}
+
+// An error is reported when it changes or the file does, not every time the file is checked.
+func TestWatcher_Load_reports_an_error_once(t *testing.T) {
+	dir, path := writeRobotsTxtFile(t, "User-agent: *\nDisallow: /cms/\n")
+	defer os.RemoveAll(dir)
+	watcher := &robotstxt.Watcher{URL: "https://www.dumpsters.com", Path: path}
+	reloads := make(chan robotstxt.Reload, 10)
+	watcher.Notify(reloads)
+	assert.Nil(t, watcher.Load())
+	<-reloads
+
+	assert.Nil(t, os.Remove(path))
+	assert.NotNil(t, watcher.Load())
+	assert.NotNil(t, watcher.Load())
+	assert.NotNil(t, (<-reloads).Err)
+	assert.Len(t, reloads, 0)
+
+	assert.Nil(t, ioutil.WriteFile(path, []byte("User-agent: *\nCrawl-delay: soon\n"), 0644))
+	assert.NotNil(t, watcher.Load())
+	assert.NotNil(t, watcher.Load())
+	assert.NotNil(t, (<-reloads).Err)
+	assert.Len(t, reloads, 0)
+
+	// The same mistake in a changed file is reported again.
+	assert.Nil(t, ioutil.WriteFile(path, []byte("User-agent: *\nCrawl-delay: soon\nDisallow: /cms/\n"), 0644))
+	assert.NotNil(t, watcher.Load())
+	assert.NotNil(t, (<-reloads).Err)
+	assert.Len(t, reloads, 0)
+
+	assert.Nil(t, ioutil.WriteFile(path, []byte("User-agent: *\nDisallow: /cms/\n"), 0644))
+	assert.Nil(t, watcher.Load())
+	assert.Nil(t, (<-reloads).Err)
+	assert.Nil(t, os.Remove(path))
+	assert.NotNil(t, watcher.Load())
+	assert.NotNil(t, (<-reloads).Err)
+}
+
+func TestWatcher_Watch(t *testing.T) {
+	dir, path := writeRobotsTxtFile(t, "User-agent: *\nDisallow: /cms/\n")
+	defer os.RemoveAll(dir)
+	watcher := &robotstxt.Watcher{URL: "https://www.dumpsters.com", Path: path, Interval: 10 * time.Millisecond}
+	reloads := make(chan robotstxt.Reload, 10)
+	watcher.Notify(reloads)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- watcher.Watch(ctx)
+	}()
+	<-reloads
+
+	assert.Nil(t, ioutil.WriteFile(path, []byte("User-agent: *\nDisallow: /pricing/\n"), 0644))
+	select {
+	case reload := <-reloads:
+		assert.Nil(t, reload.Err)
+		canCrawl, err := watcher.RobotsTxt().CanCrawl("googlebot", "/pricing/")
+		assert.Nil(t, err)
+		assert.False(t, canCrawl)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the change was never picked up")
+	}
+
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+}
+
+func TestWatcher_Get(t *testing.T) {
+	dir, path := writeRobotsTxtFile(t, "User-agent: *\nDisallow: /cms/\n")
+	defer os.RemoveAll(dir)
+	watcher := &robotstxt.Watcher{URL: "https://www.dumpsters.com", Path: path}
+
+	robotsTxt, err := watcher.Get(context.Background(), "https://www.dumpsters.com/cms/pages")
+	assert.Nil(t, err)
+	assert.Equal(t, watcher.RobotsTxt(), robotsTxt)
+
+	_, err = watcher.Get(context.Background(), "https://www.budgetdumpster.com/cms/pages")
+	assert.True(t, errors.Is(err, robotstxt.ErrOriginMismatch))
+}
+
+// writeRobotsTxtFile writes a robots.txt into a new temporary directory, the caller removes the directory.
+func writeRobotsTxtFile(t *testing.T, content string) (string, string) {
+	dir, err := ioutil.TempDir("", "robotstxt")
+	assert.Nil(t, err)
+
+	path := filepath.Join(dir, "robots.txt")
+	assert.Nil(t, ioutil.WriteFile(path, []byte(content), 0644))
+	return dir, path
+}
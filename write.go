@@ -0,0 +1,107 @@
+package robotstxt
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+WriteTo writes the RobotsTxt as robots.txt text, parsing what is written gives a RobotsTxt that allows and disallows the same URLs,
+has the same crawl delays, and the same sitemaps. Every group is written in the order it was defined:
+
+	# Comment of the group
+	User-agent: googlebot
+	User-agent: bingbot
+	Crawl-delay: 5
+	Disallow: /cms/
+	Allow: /cms/public/
+
+	Sitemap: https://www.dumpsters.com/sitemap.xml
+
+Comments of a parsed robots.txt are not kept so only comments that were added with a Builder are written.
+*/
+func (robotsTxt *RobotsTxt) WriteTo(writer io.Writer) (int64, error) {
+	countingWriter := &countingWriter{writer: writer}
+	bufferedWriter := bufio.NewWriter(countingWriter)
+
+	blankLine := false
+	if robotsTxt.comment != "" {
+		writeComment(bufferedWriter, robotsTxt.comment)
+		blankLine = true
+	}
+	for _, group := range robotsTxt.groups {
+		if blankLine {
+			_, _ = bufferedWriter.WriteString("\n")
+		}
+		writeGroup(bufferedWriter, group)
+		blankLine = true
+	}
+	if len(robotsTxt.sitemaps) > 0 && blankLine {
+		_, _ = bufferedWriter.WriteString("\n")
+	}
+	for _, sitemap := range robotsTxt.sitemaps {
+		_, _ = bufferedWriter.WriteString("Sitemap: " + sitemap + "\n")
+	}
+
+	err := bufferedWriter.Flush()
+	return countingWriter.written, err
+}
+
+// String returns the RobotsTxt as robots.txt text, see WriteTo.
+func (robotsTxt *RobotsTxt) String() string {
+	var builder strings.Builder
+	_, _ = robotsTxt.WriteTo(&builder)
+	return builder.String()
+}
+
+func writeGroup(writer *bufio.Writer, group Group) {
+	if group.Comment != "" {
+		writeComment(writer, group.Comment)
+	}
+	for _, agent := range group.Agents {
+		_, _ = writer.WriteString("User-agent: " + agent + "\n")
+	}
+	if group.CrawlDelay > 0 {
+		_, _ = writer.WriteString("Crawl-delay: " + strconv.FormatInt(crawlDelaySeconds(group.CrawlDelay), 10) + "\n")
+	}
+	for _, rule := range group.Rules {
+		if rule.Allow {
+			_, _ = writer.WriteString("Allow: " + rule.Path + "\n")
+		} else {
+			_, _ = writer.WriteString("Disallow: " + rule.Path + "\n")
+		}
+	}
+	// A group without directives would become part of the next group.
+	if len(group.Rules) == 0 && group.CrawlDelay <= 0 {
+		_, _ = writer.WriteString("Disallow:\n")
+	}
+}
+
+func writeComment(writer *bufio.Writer, comment string) {
+	for _, line := range strings.Split(comment, "\n") {
+		if line == "" {
+			_, _ = writer.WriteString("#\n")
+			continue
+		}
+		_, _ = writer.WriteString("# " + line + "\n")
+	}
+}
+
+// crawlDelaySeconds rounds up to whole seconds, the only unit a Crawl-delay can be written in, so robots never go faster than asked.
+func crawlDelaySeconds(crawlDelay time.Duration) int64 {
+	return int64((crawlDelay + time.Second - 1) / time.Second)
+}
+
+type countingWriter struct {
+	writer  io.Writer
+	written int64
+}
+
+func (countingWriter *countingWriter) Write(p []byte) (int, error) {
+	n, err := countingWriter.writer.Write(p)
+	countingWriter.written += int64(n)
+	return n, err
+}